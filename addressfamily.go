@@ -0,0 +1,212 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"iter"
+	"slices"
+)
+
+// AddressFamily describes a Linux socket address family (AF_*), such as AF_INET
+// or AF_NETLINK, as found in socket diagnostics and netlink dumps. Each
+// AddressFamily entry contains a name and the (uint16) number. It also
+// optionally contains name aliases, a comment, and the corresponding address
+// family number from the IANA "Address Family Numbers" registry, if any.
+//
+// Address families use the Linux AF_* constant names as their official names,
+// with the lowercase short names, such as "inet", as aliases.
+type AddressFamily struct {
+	Name       string   // Official address family name, such as "AF_INET".
+	Number     uint16   // Address family number value.
+	Aliases    []string // List of aliases, such as "inet".
+	Comment    string   // Entry comment, if present.
+	IANANumber uint16   // IANA Address Family Number; zero if unmapped.
+	IANAName   string   // IANA Address Family Number description, if mapped.
+}
+
+// AddressFamilyIndex indexes the known address families by either name (native
+// as well as aliases) and by number.
+//
+// When multiple address families share the same name or number, the Precedence
+// rule decides which address family becomes the primary one. The Precedence
+// rule needs to be set before merging any address families.
+type AddressFamilyIndex struct {
+	Names      map[string]*AddressFamily
+	Numbers    map[uint16]*AddressFamily
+	Precedence Precedence // Rule for picking the primary address family.
+
+	entries []*AddressFamily // all merged address families in definition order.
+}
+
+// NewAddressFamilyIndex returns an AddressFamilyIndex object initialized with
+// the specified address families.
+func NewAddressFamilyIndex(families []AddressFamily) AddressFamilyIndex {
+	i := AddressFamilyIndex{
+		Names:   map[string]*AddressFamily{},
+		Numbers: map[uint16]*AddressFamily{},
+	}
+	i.Merge(families)
+	return i
+}
+
+// Merge a list of AddressFamily descriptions into the current address families
+// index, potentially overriding existing entries in the index in case of
+// duplicates, depending on the index's Precedence rule.
+func (i *AddressFamilyIndex) Merge(families []AddressFamily) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for idx, family := range families {
+		setPrimary(i.Names, family.Name, &families[idx], firstWins)
+		for _, alias := range family.Aliases {
+			setPrimary(i.Names, alias, &families[idx], firstWins)
+		}
+		setPrimary(i.Numbers, family.Number, &families[idx], firstWins)
+		i.entries = append(i.entries, &families[idx])
+	}
+}
+
+// MergeIndex merges another AddressFamilyIndex into the current index,
+// potentially overriding existing entries in case of duplicates, depending on
+// the index's Precedence rule.
+func (i *AddressFamilyIndex) MergeIndex(afi AddressFamilyIndex) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for name, family := range afi.Names {
+		setPrimary(i.Names, name, family, firstWins)
+	}
+	for number, family := range afi.Numbers {
+		setPrimary(i.Numbers, number, family, firstWins)
+	}
+	i.entries = append(i.entries, afi.entries...)
+}
+
+// init initializes the index maps, if not already done.
+func (i *AddressFamilyIndex) init() {
+	if i.Names == nil {
+		i.Names = map[string]*AddressFamily{}
+	}
+	if i.Numbers == nil {
+		i.Numbers = map[uint16]*AddressFamily{}
+	}
+}
+
+// ByName returns the AddressFamily for the specified (alias) name, or nil if
+// not found.
+func (i *AddressFamilyIndex) ByName(name string) *AddressFamily {
+	return i.Names[name]
+}
+
+// ByNumber returns the AddressFamily for the specified address family number,
+// or nil if not found.
+func (i *AddressFamilyIndex) ByNumber(number uint16) *AddressFamily {
+	return i.Numbers[number]
+}
+
+// ByIANANumber returns the first address family in definition order that maps
+// to the specified IANA Address Family Number, or nil if there is none.
+func (i *AddressFamilyIndex) ByIANANumber(number uint16) *AddressFamily {
+	if number == 0 {
+		return nil
+	}
+	for family := range i.All() {
+		if family.IANANumber == number {
+			return family
+		}
+	}
+	return nil
+}
+
+// AllByName returns all address families with the specified (alias) name in the
+// order of their original definitions, including address families overridden by
+// later merges.
+func (i *AddressFamilyIndex) AllByName(name string) []*AddressFamily {
+	var families []*AddressFamily
+	for family := range i.All() {
+		if family.Name == name || slices.Contains(family.Aliases, name) {
+			families = append(families, family)
+		}
+	}
+	return families
+}
+
+// AllByNumber returns all address families with the specified number in the
+// order of their original definitions, including address families overridden by
+// later merges.
+func (i *AddressFamilyIndex) AllByNumber(number uint16) []*AddressFamily {
+	var families []*AddressFamily
+	for family := range i.All() {
+		if family.Number == number {
+			families = append(families, family)
+		}
+	}
+	return families
+}
+
+// All returns an iterator over all address families merged into this index, in
+// the order of their original definitions. Each address family is yielded only
+// once, regardless of its aliases, and including address families that have
+// been overridden by later merges.
+func (i *AddressFamilyIndex) All() iter.Seq[*AddressFamily] {
+	return allOf(i.entries)
+}
+
+// AddressFamilyByName returns the AddressFamily details for the specified
+// (native or aliased) name, or nil if not defined.
+func AddressFamilyByName(name string) *AddressFamily {
+	idx := defaultAddressFamilies.rlock()
+	defer defaultAddressFamilies.runlock()
+	return idx.ByName(name)
+}
+
+// AddressFamilyByNumber returns the AddressFamily details for the specified
+// address family number, or nil if not defined.
+func AddressFamilyByNumber(number uint16) *AddressFamily {
+	idx := defaultAddressFamilies.rlock()
+	defer defaultAddressFamilies.runlock()
+	return idx.ByNumber(number)
+}
+
+// AddressFamilyByIANANumber returns the AddressFamily details for the specified
+// IANA Address Family Number, or nil if not mapped.
+func AddressFamilyByIANANumber(number uint16) *AddressFamily {
+	idx := defaultAddressFamilies.rlock()
+	defer defaultAddressFamilies.runlock()
+	return idx.ByIANANumber(number)
+}
+
+// AllAddressFamilies returns an iterator over all address families in the
+// address families index, in the order of their original definitions.
+func AllAddressFamilies() iter.Seq[*AddressFamily] {
+	idx := defaultAddressFamilies.rlock()
+	defer defaultAddressFamilies.runlock()
+	return idx.All()
+}
+
+// SetAddressFamilies atomically replaces the address families index with the
+// specified index. It is safe to call SetAddressFamilies while lookups are in
+// flight in other goroutines; these lookups finish on the old index. Please
+// note that AddressFamily objects returned from the old index stay valid.
+func SetAddressFamilies(i AddressFamilyIndex) {
+	defaultAddressFamilies.set(i)
+}
+
+// AddressFamilies is the index of address family names and numbers. If left to
+// the zero value, then it will be automatically initialized with the builtin
+// definitions upon first use of AddressFamilyByName, AddressFamilyByNumber, et
+// cetera. This initialization is goroutine-safe.
+//
+// Directly assigning to AddressFamilies or merging into it is not safe while
+// lookups are in flight; use SetAddressFamilies instead.
+var AddressFamilies AddressFamilyIndex
@@ -0,0 +1,89 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("address families", func() {
+
+	BeforeEach(func() {
+		AddressFamilies = AddressFamilyIndex{}
+		DeferCleanup(func() { AddressFamilies = AddressFamilyIndex{} })
+	})
+
+	It("has unique builtin numbers and names", func() {
+		idx := NewAddressFamilyIndex(BuiltinAddressFamilies)
+		Expect(idx.Numbers).To(HaveLen(len(BuiltinAddressFamilies)))
+		for family := range idx.All() {
+			Expect(idx.AllByName(family.Name)).To(HaveLen(1), "duplicate name %q", family.Name)
+			for _, alias := range family.Aliases {
+				Expect(idx.AllByName(alias)).To(HaveLen(1), "duplicate alias %q", alias)
+			}
+		}
+	})
+
+	It("looks up builtin address families", func() {
+		Expect(AddressFamilyByNumber(2)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Name":       Equal("AF_INET"),
+			"IANANumber": Equal(uint16(1)),
+		})))
+		Expect(AddressFamilyByNumber(10).Name).To(Equal("AF_INET6"))
+		Expect(AddressFamilyByName("AF_NETLINK").Number).To(Equal(uint16(16)))
+		Expect(AddressFamilyByName("AF_ROUTE").Name).To(Equal("AF_NETLINK"))
+		Expect(AddressFamilyByName("packet").Number).To(Equal(uint16(17)))
+		Expect(AddressFamilyByName("AF_VSOCK").Number).To(Equal(uint16(40)))
+		Expect(AddressFamilyByNumber(1000)).To(BeNil())
+		Expect(slices.Collect(AllAddressFamilies())).To(HaveLen(len(BuiltinAddressFamilies)))
+	})
+
+	DescribeTable("links to IANA address family numbers",
+		func(iana uint16, name string) {
+			family := AddressFamilyByIANANumber(iana)
+			if name == "" {
+				Expect(family).To(BeNil())
+				return
+			}
+			Expect(family).NotTo(BeNil())
+			Expect(family.Name).To(Equal(name))
+		},
+		Entry(nil, uint16(1), "AF_INET"),
+		Entry(nil, uint16(2), "AF_INET6"),
+		Entry(nil, uint16(11), "AF_IPX"),
+		Entry(nil, uint16(12), "AF_APPLETALK"),
+		Entry(nil, uint16(13), "AF_DECnet"),
+		Entry(nil, uint16(0), ""),
+		Entry(nil, uint16(16), ""),
+	)
+
+	It("merges and replaces", func() {
+		idx := NewAddressFamilyIndex(BuiltinAddressFamilies)
+		idx.MergeIndex(NewAddressFamilyIndex([]AddressFamily{
+			{Name: "AF_FOO", Number: 2},
+		}))
+		Expect(idx.ByNumber(2).Name).To(Equal("AF_FOO"))
+		Expect(idx.AllByNumber(2)).To(HaveLen(2))
+
+		SetAddressFamilies(idx)
+		Expect(AddressFamilyByNumber(2).Name).To(Equal("AF_FOO"))
+		Expect(AddressFamilyByIANANumber(1).Name).To(Equal("AF_INET"))
+	})
+
+})
@@ -0,0 +1,70 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+// BuiltinAddressFamilies lists the Linux socket address families (AF_*) from
+// the Linux kernel header include/linux/socket.h, with the header's comments
+// as comments. The address families link to the corresponding entries of the
+// IANA "Address Family Numbers" registry at
+// https://www.iana.org/assignments/address-family-numbers, where such a
+// mapping exists.
+var BuiltinAddressFamilies = []AddressFamily{
+	{Name: "AF_UNSPEC", Number: 0, Aliases: []string{"unspec"}},
+	{Name: "AF_UNIX", Number: 1, Aliases: []string{"unix", "AF_LOCAL", "AF_FILE"}, Comment: "Unix domain sockets"},
+	{Name: "AF_INET", Number: 2, Aliases: []string{"inet"}, Comment: "Internet IP Protocol", IANANumber: 1, IANAName: "IP (IP version 4)"},
+	{Name: "AF_AX25", Number: 3, Aliases: []string{"ax25"}, Comment: "Amateur Radio AX.25"},
+	{Name: "AF_IPX", Number: 4, Aliases: []string{"ipx"}, Comment: "Novell IPX", IANANumber: 11, IANAName: "IPX"},
+	{Name: "AF_APPLETALK", Number: 5, Aliases: []string{"appletalk"}, Comment: "AppleTalk DDP", IANANumber: 12, IANAName: "Appletalk"},
+	{Name: "AF_NETROM", Number: 6, Aliases: []string{"netrom"}, Comment: "Amateur Radio NET/ROM"},
+	{Name: "AF_BRIDGE", Number: 7, Aliases: []string{"bridge"}, Comment: "Multiprotocol bridge"},
+	{Name: "AF_ATMPVC", Number: 8, Aliases: []string{"atmpvc"}, Comment: "ATM PVCs"},
+	{Name: "AF_X25", Number: 9, Aliases: []string{"x25"}, Comment: "Reserved for X.25 project"},
+	{Name: "AF_INET6", Number: 10, Aliases: []string{"inet6"}, Comment: "IP version 6", IANANumber: 2, IANAName: "IP6 (IP version 6)"},
+	{Name: "AF_ROSE", Number: 11, Aliases: []string{"rose"}, Comment: "Amateur Radio X.25 PLP"},
+	{Name: "AF_DECnet", Number: 12, Aliases: []string{"decnet"}, Comment: "Reserved for DECnet project", IANANumber: 13, IANAName: "DECnet IV"},
+	{Name: "AF_NETBEUI", Number: 13, Aliases: []string{"netbeui"}, Comment: "Reserved for 802.2LLC project"},
+	{Name: "AF_SECURITY", Number: 14, Aliases: []string{"security"}, Comment: "Security callback pseudo AF"},
+	{Name: "AF_KEY", Number: 15, Aliases: []string{"key"}, Comment: "PF_KEY key management API"},
+	{Name: "AF_NETLINK", Number: 16, Aliases: []string{"netlink", "AF_ROUTE"}},
+	{Name: "AF_PACKET", Number: 17, Aliases: []string{"packet"}, Comment: "Packet family"},
+	{Name: "AF_ASH", Number: 18, Aliases: []string{"ash"}, Comment: "Ash"},
+	{Name: "AF_ECONET", Number: 19, Aliases: []string{"econet"}, Comment: "Acorn Econet"},
+	{Name: "AF_ATMSVC", Number: 20, Aliases: []string{"atmsvc"}, Comment: "ATM SVCs"},
+	{Name: "AF_RDS", Number: 21, Aliases: []string{"rds"}, Comment: "RDS sockets"},
+	{Name: "AF_SNA", Number: 22, Aliases: []string{"sna"}, Comment: "Linux SNA Project"},
+	{Name: "AF_IRDA", Number: 23, Aliases: []string{"irda"}, Comment: "IRDA sockets"},
+	{Name: "AF_PPPOX", Number: 24, Aliases: []string{"pppox"}, Comment: "PPPoX sockets"},
+	{Name: "AF_WANPIPE", Number: 25, Aliases: []string{"wanpipe"}, Comment: "Wanpipe API Sockets"},
+	{Name: "AF_LLC", Number: 26, Aliases: []string{"llc"}, Comment: "Linux LLC"},
+	{Name: "AF_IB", Number: 27, Aliases: []string{"ib"}, Comment: "Native InfiniBand address"},
+	{Name: "AF_MPLS", Number: 28, Aliases: []string{"mpls"}, Comment: "MPLS"},
+	{Name: "AF_CAN", Number: 29, Aliases: []string{"can"}, Comment: "Controller Area Network"},
+	{Name: "AF_TIPC", Number: 30, Aliases: []string{"tipc"}, Comment: "TIPC sockets"},
+	{Name: "AF_BLUETOOTH", Number: 31, Aliases: []string{"bluetooth"}, Comment: "Bluetooth sockets"},
+	{Name: "AF_IUCV", Number: 32, Aliases: []string{"iucv"}, Comment: "IUCV sockets"},
+	{Name: "AF_RXRPC", Number: 33, Aliases: []string{"rxrpc"}, Comment: "RxRPC sockets"},
+	{Name: "AF_ISDN", Number: 34, Aliases: []string{"isdn"}, Comment: "mISDN sockets"},
+	{Name: "AF_PHONET", Number: 35, Aliases: []string{"phonet"}, Comment: "Phonet sockets"},
+	{Name: "AF_IEEE802154", Number: 36, Aliases: []string{"ieee802154"}, Comment: "IEEE802154 sockets"},
+	{Name: "AF_CAIF", Number: 37, Aliases: []string{"caif"}, Comment: "CAIF sockets"},
+	{Name: "AF_ALG", Number: 38, Aliases: []string{"alg"}, Comment: "Algorithm sockets"},
+	{Name: "AF_NFC", Number: 39, Aliases: []string{"nfc"}, Comment: "NFC sockets"},
+	{Name: "AF_VSOCK", Number: 40, Aliases: []string{"vsock"}, Comment: "vSockets"},
+	{Name: "AF_KCM", Number: 41, Aliases: []string{"kcm"}, Comment: "Kernel Connection Multiplexor"},
+	{Name: "AF_QIPCRTR", Number: 42, Aliases: []string{"qipcrtr"}, Comment: "Qualcomm IPC Router"},
+	{Name: "AF_SMC", Number: 43, Aliases: []string{"smc"}, Comment: "smc sockets"},
+	{Name: "AF_XDP", Number: 44, Aliases: []string{"xdp"}, Comment: "XDP sockets"},
+	{Name: "AF_MCTP", Number: 45, Aliases: []string{"mctp"}, Comment: "Management component transport protocol"},
+}
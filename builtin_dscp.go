@@ -0,0 +1,53 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+// BuiltinDSCPs lists the standardized DSCPs, compiled from the IANA
+// "Differentiated Services Field Codepoints (DSCP)" registry at
+// https://www.iana.org/assignments/dscp-registry and the RFCs defining the
+// per-hop behaviors.
+var BuiltinDSCPs = []Codepoint{
+	{Name: "CS0", Number: 0, Aliases: []string{"DF", "default"}, Description: "Class Selector 0, Default Forwarding", Reference: "[RFC2474]"},
+	{Name: "LE", Number: 1, Description: "Lower Effort", Reference: "[RFC8622]"},
+	{Name: "CS1", Number: 8, Description: "Class Selector 1", Reference: "[RFC2474]"},
+	{Name: "AF11", Number: 10, Description: "Assured Forwarding class 1, low drop precedence", Reference: "[RFC2597]"},
+	{Name: "AF12", Number: 12, Description: "Assured Forwarding class 1, medium drop precedence", Reference: "[RFC2597]"},
+	{Name: "AF13", Number: 14, Description: "Assured Forwarding class 1, high drop precedence", Reference: "[RFC2597]"},
+	{Name: "CS2", Number: 16, Description: "Class Selector 2", Reference: "[RFC2474]"},
+	{Name: "AF21", Number: 18, Description: "Assured Forwarding class 2, low drop precedence", Reference: "[RFC2597]"},
+	{Name: "AF22", Number: 20, Description: "Assured Forwarding class 2, medium drop precedence", Reference: "[RFC2597]"},
+	{Name: "AF23", Number: 22, Description: "Assured Forwarding class 2, high drop precedence", Reference: "[RFC2597]"},
+	{Name: "CS3", Number: 24, Description: "Class Selector 3", Reference: "[RFC2474]"},
+	{Name: "AF31", Number: 26, Description: "Assured Forwarding class 3, low drop precedence", Reference: "[RFC2597]"},
+	{Name: "AF32", Number: 28, Description: "Assured Forwarding class 3, medium drop precedence", Reference: "[RFC2597]"},
+	{Name: "AF33", Number: 30, Description: "Assured Forwarding class 3, high drop precedence", Reference: "[RFC2597]"},
+	{Name: "CS4", Number: 32, Description: "Class Selector 4", Reference: "[RFC2474]"},
+	{Name: "AF41", Number: 34, Description: "Assured Forwarding class 4, low drop precedence", Reference: "[RFC2597]"},
+	{Name: "AF42", Number: 36, Description: "Assured Forwarding class 4, medium drop precedence", Reference: "[RFC2597]"},
+	{Name: "AF43", Number: 38, Description: "Assured Forwarding class 4, high drop precedence", Reference: "[RFC2597]"},
+	{Name: "CS5", Number: 40, Description: "Class Selector 5", Reference: "[RFC2474]"},
+	{Name: "VOICE-ADMIT", Number: 44, Description: "Capacity-Admitted Traffic", Reference: "[RFC5865]"},
+	{Name: "EF", Number: 46, Description: "Expedited Forwarding", Reference: "[RFC3246]"},
+	{Name: "CS6", Number: 48, Description: "Class Selector 6", Reference: "[RFC2474]"},
+	{Name: "CS7", Number: 56, Description: "Class Selector 7", Reference: "[RFC2474]"},
+}
+
+// BuiltinECNs lists the ECN codepoints as defined in RFC 3168.
+var BuiltinECNs = []Codepoint{
+	{Name: "Not-ECT", Number: 0, Description: "Not ECN-Capable Transport", Reference: "[RFC3168]"},
+	{Name: "ECT(1)", Number: 1, Aliases: []string{"ECT1"}, Description: "ECN-Capable Transport", Reference: "[RFC3168]"},
+	{Name: "ECT(0)", Number: 2, Aliases: []string{"ECT0"}, Description: "ECN-Capable Transport", Reference: "[RFC3168]"},
+	{Name: "CE", Number: 3, Description: "Congestion Experienced", Reference: "[RFC3168]"},
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+// BuiltinHardwareTypes lists the Linux ARPHRD_* hardware types from the Linux
+// UAPI header include/uapi/linux/if_arp.h, followed by the remaining hardware
+// types from the IANA "Hardware Types" registry at
+// https://www.iana.org/assignments/arp-parameters.
+//
+// The names of the Linux hardware types follow iproute2's "link/..." names,
+// with the ARPHRD_* constant names as aliases and the if_arp.h comments as
+// comments. IANA hardware types only appear when Linux doesn't use the same
+// number for a different hardware type; they are named after their IANA
+// descriptions, which also serve as their comments.
+var BuiltinHardwareTypes = []HardwareType{
+	{Name: "netrom", Number: 0, Aliases: []string{"ARPHRD_NETROM"}, Comment: "from KA9Q: NET/ROM pseudo"},
+	{Name: "ether", Number: 1, Aliases: []string{"ARPHRD_ETHER"}, Comment: "Ethernet 10Mbps"},
+	{Name: "eether", Number: 2, Aliases: []string{"ARPHRD_EETHER"}, Comment: "Experimental Ethernet"},
+	{Name: "ax25", Number: 3, Aliases: []string{"ARPHRD_AX25"}, Comment: "AX.25 Level 2"},
+	{Name: "pronet", Number: 4, Aliases: []string{"ARPHRD_PRONET"}, Comment: "PROnet token ring"},
+	{Name: "chaos", Number: 5, Aliases: []string{"ARPHRD_CHAOS"}, Comment: "Chaosnet"},
+	{Name: "ieee802", Number: 6, Aliases: []string{"ARPHRD_IEEE802"}, Comment: "IEEE 802.2 Ethernet/TR/TB"},
+	{Name: "arcnet", Number: 7, Aliases: []string{"ARPHRD_ARCNET"}, Comment: "ARCnet"},
+	{Name: "atalk", Number: 8, Aliases: []string{"ARPHRD_APPLETLK"}, Comment: "APPLEtalk"},
+	{Name: "dlci", Number: 15, Aliases: []string{"ARPHRD_DLCI"}, Comment: "Frame Relay DLCI"},
+	{Name: "atm", Number: 19, Aliases: []string{"ARPHRD_ATM"}, Comment: "ATM"},
+	{Name: "metricom", Number: 23, Aliases: []string{"ARPHRD_METRICOM"}, Comment: "Metricom STRIP (new IANA id)"},
+	{Name: "ieee1394", Number: 24, Aliases: []string{"ARPHRD_IEEE1394"}, Comment: "IEEE 1394 IPv4 - RFC 2734"},
+	{Name: "eui64", Number: 27, Aliases: []string{"ARPHRD_EUI64"}, Comment: "EUI-64"},
+	{Name: "infiniband", Number: 32, Aliases: []string{"ARPHRD_INFINIBAND"}, Comment: "InfiniBand"},
+	{Name: "slip", Number: 256, Aliases: []string{"ARPHRD_SLIP"}, Comment: ""},
+	{Name: "cslip", Number: 257, Aliases: []string{"ARPHRD_CSLIP"}, Comment: ""},
+	{Name: "slip6", Number: 258, Aliases: []string{"ARPHRD_SLIP6"}, Comment: ""},
+	{Name: "cslip6", Number: 259, Aliases: []string{"ARPHRD_CSLIP6"}, Comment: ""},
+	{Name: "rsrvd", Number: 260, Aliases: []string{"ARPHRD_RSRVD"}, Comment: "Notional KISS type"},
+	{Name: "adapt", Number: 264, Aliases: []string{"ARPHRD_ADAPT"}, Comment: ""},
+	{Name: "rose", Number: 270, Aliases: []string{"ARPHRD_ROSE"}, Comment: ""},
+	{Name: "x25", Number: 271, Aliases: []string{"ARPHRD_X25"}, Comment: "CCITT X.25"},
+	{Name: "hwx25", Number: 272, Aliases: []string{"ARPHRD_HWX25"}, Comment: "Boards with X.25 in firmware"},
+	{Name: "can", Number: 280, Aliases: []string{"ARPHRD_CAN"}, Comment: "Controller Area Network"},
+	{Name: "mctp", Number: 290, Aliases: []string{"ARPHRD_MCTP"}, Comment: ""},
+	{Name: "ppp", Number: 512, Aliases: []string{"ARPHRD_PPP"}, Comment: ""},
+	{Name: "hdlc", Number: 513, Aliases: []string{"ARPHRD_CISCO"}, Comment: "Cisco HDLC"},
+	{Name: "lapb", Number: 516, Aliases: []string{"ARPHRD_LAPB"}, Comment: "LAPB"},
+	{Name: "ddcmp", Number: 517, Aliases: []string{"ARPHRD_DDCMP"}, Comment: "Digital's DDCMP protocol"},
+	{Name: "rawhdlc", Number: 518, Aliases: []string{"ARPHRD_RAWHDLC"}, Comment: "Raw HDLC"},
+	{Name: "rawip", Number: 519, Aliases: []string{"ARPHRD_RAWIP"}, Comment: "Raw IP"},
+	{Name: "tunnel", Number: 768, Aliases: []string{"ARPHRD_TUNNEL"}, Comment: "IPIP tunnel"},
+	{Name: "tunnel6", Number: 769, Aliases: []string{"ARPHRD_TUNNEL6"}, Comment: "IP6IP6 tunnel"},
+	{Name: "frad", Number: 770, Aliases: []string{"ARPHRD_FRAD"}, Comment: "Frame Relay Access Device"},
+	{Name: "skip", Number: 771, Aliases: []string{"ARPHRD_SKIP"}, Comment: "SKIP vif"},
+	{Name: "loopback", Number: 772, Aliases: []string{"ARPHRD_LOOPBACK"}, Comment: "Loopback device"},
+	{Name: "ltalk", Number: 773, Aliases: []string{"ARPHRD_LOCALTLK"}, Comment: "Localtalk device"},
+	{Name: "fddi", Number: 774, Aliases: []string{"ARPHRD_FDDI"}, Comment: "Fiber Distributed Data Interface"},
+	{Name: "bif", Number: 775, Aliases: []string{"ARPHRD_BIF"}, Comment: "AP1000 BIF"},
+	{Name: "sit", Number: 776, Aliases: []string{"ARPHRD_SIT"}, Comment: "sit0 device - IPv6-in-IPv4"},
+	{Name: "ip/ddp", Number: 777, Aliases: []string{"ARPHRD_IPDDP"}, Comment: "IP over DDP tunneller"},
+	{Name: "gre", Number: 778, Aliases: []string{"ARPHRD_IPGRE"}, Comment: "GRE over IP"},
+	{Name: "pimreg", Number: 779, Aliases: []string{"ARPHRD_PIMREG"}, Comment: "PIMSM register interface"},
+	{Name: "hippi", Number: 780, Aliases: []string{"ARPHRD_HIPPI"}, Comment: "High Performance Parallel Interface"},
+	{Name: "ash", Number: 781, Aliases: []string{"ARPHRD_ASH"}, Comment: "Nexus 64Mbps Ash"},
+	{Name: "econet", Number: 782, Aliases: []string{"ARPHRD_ECONET"}, Comment: "Acorn Econet"},
+	{Name: "irda", Number: 783, Aliases: []string{"ARPHRD_IRDA"}, Comment: "Linux-IrDA"},
+	{Name: "fcpp", Number: 784, Aliases: []string{"ARPHRD_FCPP"}, Comment: "Point to point fibrechannel"},
+	{Name: "fcal", Number: 785, Aliases: []string{"ARPHRD_FCAL"}, Comment: "Fibrechannel arbitrated loop"},
+	{Name: "fcpl", Number: 786, Aliases: []string{"ARPHRD_FCPL"}, Comment: "Fibrechannel public loop"},
+	{Name: "fcfb0", Number: 787, Aliases: []string{"ARPHRD_FCFABRIC"}, Comment: "Fibrechannel fabric"},
+	{Name: "tr", Number: 800, Aliases: []string{"ARPHRD_IEEE802_TR"}, Comment: "Magic type ident for TR"},
+	{Name: "ieee802.11", Number: 801, Aliases: []string{"ARPHRD_IEEE80211"}, Comment: "IEEE 802.11"},
+	{Name: "ieee802.11/prism", Number: 802, Aliases: []string{"ARPHRD_IEEE80211_PRISM"}, Comment: "IEEE 802.11 + Prism2 header"},
+	{Name: "ieee802.11/radiotap", Number: 803, Aliases: []string{"ARPHRD_IEEE80211_RADIOTAP"}, Comment: "IEEE 802.11 + radiotap header"},
+	{Name: "ieee802.15.4", Number: 804, Aliases: []string{"ARPHRD_IEEE802154"}, Comment: ""},
+	{Name: "ieee802.15.4/monitor", Number: 805, Aliases: []string{"ARPHRD_IEEE802154_MONITOR"}, Comment: "IEEE 802.15.4 network monitor"},
+	{Name: "phonet", Number: 820, Aliases: []string{"ARPHRD_PHONET"}, Comment: "PhoNet media type"},
+	{Name: "phonet_pipe", Number: 821, Aliases: []string{"ARPHRD_PHONET_PIPE"}, Comment: "PhoNet pipe header"},
+	{Name: "caif", Number: 822, Aliases: []string{"ARPHRD_CAIF"}, Comment: "CAIF media type"},
+	{Name: "gre6", Number: 823, Aliases: []string{"ARPHRD_IP6GRE"}, Comment: "GRE over IPv6"},
+	{Name: "netlink", Number: 824, Aliases: []string{"ARPHRD_NETLINK"}, Comment: "Netlink header"},
+	{Name: "6lowpan", Number: 825, Aliases: []string{"ARPHRD_6LOWPAN"}, Comment: "IPv6 over LoWPAN"},
+	{Name: "vsockmon", Number: 826, Aliases: []string{"ARPHRD_VSOCKMON"}, Comment: "Vsock monitor header"},
+	{Name: "none", Number: 65534, Aliases: []string{"ARPHRD_NONE"}, Comment: "zero header length"},
+	{Name: "void", Number: 65535, Aliases: []string{"ARPHRD_VOID"}, Comment: "Void type, nothing is known"},
+	{Name: "lanstar", Number: 9, Comment: "Lanstar"},
+	{Name: "autonet", Number: 10, Comment: "Autonet Short Address"},
+	{Name: "localtalk", Number: 11, Comment: "LocalTalk"},
+	{Name: "localnet", Number: 12, Comment: "LocalNet (IBM PCNet or SYTEK LocalNET)"},
+	{Name: "ultralink", Number: 13, Comment: "Ultra link"},
+	{Name: "smds", Number: 14, Comment: "SMDS"},
+	{Name: "atm-16", Number: 16, Comment: "Asynchronous Transmission Mode (ATM)"},
+	{Name: "iana-hdlc", Number: 17, Comment: "HDLC"},
+	{Name: "fibre-channel", Number: 18, Comment: "Fibre Channel"},
+	{Name: "serial-line", Number: 20, Comment: "Serial Line"},
+	{Name: "atm-21", Number: 21, Comment: "Asynchronous Transmission Mode (ATM)"},
+	{Name: "mil-std-188-220", Number: 22, Comment: "MIL-STD-188-220"},
+	{Name: "mapos", Number: 25, Comment: "MAPOS"},
+	{Name: "twinaxial", Number: 26, Comment: "Twinaxial"},
+	{Name: "hiparp", Number: 28, Comment: "HIPARP"},
+	{Name: "iso7816-3", Number: 29, Comment: "IP and ARP over ISO 7816-3"},
+	{Name: "arpsec", Number: 30, Comment: "ARPSec"},
+	{Name: "ipsec-tunnel", Number: 31, Comment: "IPsec tunnel"},
+	{Name: "tia-102-p25-cai", Number: 33, Comment: "TIA-102 Project 25 Common Air Interface (CAI)"},
+	{Name: "wiegand", Number: 34, Comment: "Wiegand Interface"},
+	{Name: "pure-ip", Number: 35, Comment: "Pure IP"},
+	{Name: "hw-exp1", Number: 36, Comment: "HW_EXP1"},
+	{Name: "hfi", Number: 37, Comment: "HFI"},
+	{Name: "unified-bus", Number: 38, Comment: "Unified Bus (UB)"},
+}
@@ -0,0 +1,193 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+// BuiltinICMPv4Types lists the assigned ICMP message types and codes, compiled
+// from the IANA "Internet Control Message Protocol (ICMP) Parameters" registry
+// at https://www.iana.org/assignments/icmp-parameters. Unassigned, reserved,
+// and private experimentation types are not included. Where available, the
+// short names follow the names used by iptables and nftables.
+var BuiltinICMPv4Types = []ICMPType{
+	{Type: 0, Name: "echo-reply", Description: "Echo Reply", Reference: "[RFC792]"},
+	{Type: 3, Name: "destination-unreachable", Description: "Destination Unreachable", Reference: "[RFC792]", Codes: []ICMPCode{
+		{Code: 0, Name: "network-unreachable", Description: "Net Unreachable"},
+		{Code: 1, Name: "host-unreachable", Description: "Host Unreachable"},
+		{Code: 2, Name: "protocol-unreachable", Description: "Protocol Unreachable"},
+		{Code: 3, Name: "port-unreachable", Description: "Port Unreachable"},
+		{Code: 4, Name: "fragmentation-needed", Description: "Fragmentation Needed and Don't Fragment was Set"},
+		{Code: 5, Name: "source-route-failed", Description: "Source Route Failed"},
+		{Code: 6, Name: "network-unknown", Description: "Destination Network Unknown"},
+		{Code: 7, Name: "host-unknown", Description: "Destination Host Unknown"},
+		{Code: 8, Name: "source-host-isolated", Description: "Source Host Isolated"},
+		{Code: 9, Name: "network-prohibited", Description: "Communication with Destination Network is Administratively Prohibited"},
+		{Code: 10, Name: "host-prohibited", Description: "Communication with Destination Host is Administratively Prohibited"},
+		{Code: 11, Name: "tos-network-unreachable", Description: "Destination Network Unreachable for Type of Service"},
+		{Code: 12, Name: "tos-host-unreachable", Description: "Destination Host Unreachable for Type of Service"},
+		{Code: 13, Name: "communication-prohibited", Description: "Communication Administratively Prohibited"},
+		{Code: 14, Name: "host-precedence-violation", Description: "Host Precedence Violation"},
+		{Code: 15, Name: "precedence-cutoff", Description: "Precedence cutoff in effect"},
+	}},
+	{Type: 4, Name: "source-quench", Description: "Source Quench", Reference: "[RFC792][RFC6633]", Deprecated: true},
+	{Type: 5, Name: "redirect", Description: "Redirect", Reference: "[RFC792]", Codes: []ICMPCode{
+		{Code: 0, Name: "network-redirect", Description: "Redirect Datagram for the Network (or subnet)"},
+		{Code: 1, Name: "host-redirect", Description: "Redirect Datagram for the Host"},
+		{Code: 2, Name: "tos-network-redirect", Description: "Redirect Datagram for the Type of Service and Network"},
+		{Code: 3, Name: "tos-host-redirect", Description: "Redirect Datagram for the Type of Service and Host"},
+	}},
+	{Type: 6, Name: "alternate-host-address", Description: "Alternate Host Address", Reference: "[RFC6918]", Deprecated: true},
+	{Type: 8, Name: "echo-request", Description: "Echo", Reference: "[RFC792]"},
+	{Type: 9, Name: "router-advertisement", Description: "Router Advertisement", Reference: "[RFC1256]", Codes: []ICMPCode{
+		{Code: 0, Name: "normal-router-advertisement", Description: "Normal router advertisement"},
+		{Code: 16, Name: "does-not-route-common-traffic", Description: "Does not route common traffic"},
+	}},
+	{Type: 10, Name: "router-solicitation", Description: "Router Selection", Reference: "[RFC1256]"},
+	{Type: 11, Name: "time-exceeded", Description: "Time Exceeded", Reference: "[RFC792]", Codes: []ICMPCode{
+		{Code: 0, Name: "ttl-zero-during-transit", Description: "Time to Live exceeded in Transit"},
+		{Code: 1, Name: "ttl-zero-during-reassembly", Description: "Fragment Reassembly Time Exceeded"},
+	}},
+	{Type: 12, Name: "parameter-problem", Description: "Parameter Problem", Reference: "[RFC792]", Codes: []ICMPCode{
+		{Code: 0, Name: "ip-header-bad", Description: "Pointer indicates the error"},
+		{Code: 1, Name: "required-option-missing", Description: "Missing a Required Option"},
+		{Code: 2, Name: "bad-length", Description: "Bad Length"},
+	}},
+	{Type: 13, Name: "timestamp-request", Description: "Timestamp", Reference: "[RFC792]"},
+	{Type: 14, Name: "timestamp-reply", Description: "Timestamp Reply", Reference: "[RFC792]"},
+	{Type: 15, Name: "info-request", Description: "Information Request", Reference: "[RFC792][RFC6918]", Deprecated: true},
+	{Type: 16, Name: "info-reply", Description: "Information Reply", Reference: "[RFC792][RFC6918]", Deprecated: true},
+	{Type: 17, Name: "address-mask-request", Description: "Address Mask Request", Reference: "[RFC950][RFC6918]", Deprecated: true},
+	{Type: 18, Name: "address-mask-reply", Description: "Address Mask Reply", Reference: "[RFC950][RFC6918]", Deprecated: true},
+	{Type: 30, Name: "traceroute", Description: "Traceroute", Reference: "[RFC1393][RFC6918]", Deprecated: true},
+	{Type: 31, Name: "datagram-conversion-error", Description: "Datagram Conversion Error", Reference: "[RFC1475][RFC6918]", Deprecated: true},
+	{Type: 32, Name: "mobile-host-redirect", Description: "Mobile Host Redirect", Reference: "[David_Johnson][RFC6918]", Deprecated: true},
+	{Type: 33, Name: "ipv6-where-are-you", Description: "IPv6 Where-Are-You", Reference: "[Bill_Simpson][RFC6918]", Deprecated: true},
+	{Type: 34, Name: "ipv6-i-am-here", Description: "IPv6 I-Am-Here", Reference: "[Bill_Simpson][RFC6918]", Deprecated: true},
+	{Type: 35, Name: "mobile-registration-request", Description: "Mobile Registration Request", Reference: "[Bill_Simpson][RFC6918]", Deprecated: true},
+	{Type: 36, Name: "mobile-registration-reply", Description: "Mobile Registration Reply", Reference: "[Bill_Simpson][RFC6918]", Deprecated: true},
+	{Type: 37, Name: "domain-name-request", Description: "Domain Name Request", Reference: "[RFC1788][RFC6918]", Deprecated: true},
+	{Type: 38, Name: "domain-name-reply", Description: "Domain Name Reply", Reference: "[RFC1788][RFC6918]", Deprecated: true},
+	{Type: 39, Name: "skip", Description: "SKIP", Reference: "[Tom_Markson][RFC6918]", Deprecated: true},
+	{Type: 40, Name: "photuris", Description: "Photuris", Reference: "[RFC2521]", Codes: []ICMPCode{
+		{Code: 0, Name: "bad-spi", Description: "Bad SPI"},
+		{Code: 1, Name: "authentication-failed", Description: "Authentication Failed"},
+		{Code: 2, Name: "decompression-failed", Description: "Decompression Failed"},
+		{Code: 3, Name: "decryption-failed", Description: "Decryption Failed"},
+		{Code: 4, Name: "need-authentication", Description: "Need Authentication"},
+		{Code: 5, Name: "need-authorization", Description: "Need Authorization"},
+	}},
+	{Type: 41, Name: "experimental-mobility", Description: "ICMP messages utilized by experimental mobility protocols such as Seamoby", Reference: "[RFC4065]"},
+	{Type: 42, Name: "extended-echo-request", Description: "Extended Echo Request", Reference: "[RFC8335]", Codes: []ICMPCode{
+		{Code: 0, Name: "no-error", Description: "No Error"},
+	}},
+	{Type: 43, Name: "extended-echo-reply", Description: "Extended Echo Reply", Reference: "[RFC8335]", Codes: []ICMPCode{
+		{Code: 0, Name: "no-error", Description: "No Error"},
+		{Code: 1, Name: "malformed-query", Description: "Malformed Query"},
+		{Code: 2, Name: "no-such-interface", Description: "No Such Interface"},
+		{Code: 3, Name: "no-such-table-entry", Description: "No Such Table Entry"},
+		{Code: 4, Name: "multiple-interfaces-satisfy-query", Description: "Multiple Interfaces Satisfy Query"},
+	}},
+	{Type: 253, Name: "experiment-1", Description: "RFC3692-style Experiment 1", Reference: "[RFC4727]"},
+	{Type: 254, Name: "experiment-2", Description: "RFC3692-style Experiment 2", Reference: "[RFC4727]"},
+}
+
+// BuiltinICMPv6Types lists the assigned ICMPv6 message types and codes,
+// compiled from the IANA "Internet Control Message Protocol version 6 (ICMPv6)
+// Parameters" registry at https://www.iana.org/assignments/icmpv6-parameters.
+// Unassigned, reserved, and private experimentation types are not included.
+// Where available, the short names follow the names used by nftables.
+var BuiltinICMPv6Types = []ICMPType{
+	{Type: 1, Name: "destination-unreachable", Description: "Destination Unreachable", Reference: "[RFC4443]", Codes: []ICMPCode{
+		{Code: 0, Name: "no-route", Description: "no route to destination"},
+		{Code: 1, Name: "admin-prohibited", Description: "communication with destination administratively prohibited"},
+		{Code: 2, Name: "beyond-scope", Description: "beyond scope of source address"},
+		{Code: 3, Name: "addr-unreachable", Description: "address unreachable"},
+		{Code: 4, Name: "port-unreachable", Description: "port unreachable"},
+		{Code: 5, Name: "policy-fail", Description: "source address failed ingress/egress policy"},
+		{Code: 6, Name: "reject-route", Description: "reject route to destination"},
+		{Code: 7, Name: "source-routing-header-error", Description: "Error in Source Routing Header"},
+		{Code: 8, Name: "headers-too-long", Description: "Headers too long"},
+	}},
+	{Type: 2, Name: "packet-too-big", Description: "Packet Too Big", Reference: "[RFC4443]"},
+	{Type: 3, Name: "time-exceeded", Description: "Time Exceeded", Reference: "[RFC4443]", Codes: []ICMPCode{
+		{Code: 0, Name: "hop-limit-exceeded", Description: "hop limit exceeded in transit"},
+		{Code: 1, Name: "fragment-reassembly-time-exceeded", Description: "fragment reassembly time exceeded"},
+	}},
+	{Type: 4, Name: "parameter-problem", Description: "Parameter Problem", Reference: "[RFC4443]", Codes: []ICMPCode{
+		{Code: 0, Name: "erroneous-header-field", Description: "erroneous header field encountered"},
+		{Code: 1, Name: "unrecognized-next-header", Description: "unrecognized Next Header type encountered"},
+		{Code: 2, Name: "unrecognized-option", Description: "unrecognized IPv6 option encountered"},
+		{Code: 3, Name: "incomplete-header-chain", Description: "IPv6 First Fragment has incomplete IPv6 Header Chain"},
+		{Code: 4, Name: "sr-upper-layer-header-error", Description: "SR Upper-layer Header Error"},
+		{Code: 5, Name: "unrecognized-next-header-by-intermediate-node", Description: "Unrecognized Next Header type encountered by intermediate node"},
+		{Code: 6, Name: "extension-header-too-big", Description: "Extension header too big"},
+		{Code: 7, Name: "extension-header-chain-too-long", Description: "Extension header chain too long"},
+		{Code: 8, Name: "too-many-extension-headers", Description: "Too many extension headers"},
+		{Code: 9, Name: "too-many-options", Description: "Too many options in extension header"},
+		{Code: 10, Name: "option-too-big", Description: "Option too big"},
+	}},
+	{Type: 128, Name: "echo-request", Description: "Echo Request", Reference: "[RFC4443]"},
+	{Type: 129, Name: "echo-reply", Description: "Echo Reply", Reference: "[RFC4443]"},
+	{Type: 130, Name: "mld-listener-query", Description: "Multicast Listener Query", Reference: "[RFC2710]"},
+	{Type: 131, Name: "mld-listener-report", Description: "Multicast Listener Report", Reference: "[RFC2710]"},
+	{Type: 132, Name: "mld-listener-done", Description: "Multicast Listener Done", Reference: "[RFC2710]"},
+	{Type: 133, Name: "nd-router-solicit", Description: "Router Solicitation", Reference: "[RFC4861]"},
+	{Type: 134, Name: "nd-router-advert", Description: "Router Advertisement", Reference: "[RFC4861]"},
+	{Type: 135, Name: "nd-neighbor-solicit", Description: "Neighbor Solicitation", Reference: "[RFC4861]"},
+	{Type: 136, Name: "nd-neighbor-advert", Description: "Neighbor Advertisement", Reference: "[RFC4861]"},
+	{Type: 137, Name: "nd-redirect", Description: "Redirect Message", Reference: "[RFC4861]"},
+	{Type: 138, Name: "router-renumbering", Description: "Router Renumbering", Reference: "[Matt_Crawford]", Codes: []ICMPCode{
+		{Code: 0, Name: "router-renumbering-command", Description: "Router Renumbering Command"},
+		{Code: 1, Name: "router-renumbering-result", Description: "Router Renumbering Result"},
+		{Code: 255, Name: "sequence-number-reset", Description: "Sequence Number Reset"},
+	}},
+	{Type: 139, Name: "node-information-query", Description: "ICMP Node Information Query", Reference: "[RFC4620]", Codes: []ICMPCode{
+		{Code: 0, Name: "ipv6-address-subject", Description: "The Data field contains an IPv6 address which is the Subject of this Query"},
+		{Code: 1, Name: "name-subject", Description: "The Data field contains a name which is the Subject of this Query, or is empty, as in the case of a NOOP"},
+		{Code: 2, Name: "ipv4-address-subject", Description: "The Data field contains an IPv4 address which is the Subject of this Query"},
+	}},
+	{Type: 140, Name: "node-information-response", Description: "ICMP Node Information Response", Reference: "[RFC4620]", Codes: []ICMPCode{
+		{Code: 0, Name: "successful-reply", Description: "A successful reply. The Reply Data field may or may not be empty"},
+		{Code: 1, Name: "refused", Description: "The Responder refuses to supply the answer. The Reply Data field will be empty"},
+		{Code: 2, Name: "unknown-qtype", Description: "The Qtype of the Query is unknown to the Responder. The Reply Data field will be empty"},
+	}},
+	{Type: 141, Name: "ind-neighbor-solicit", Description: "Inverse Neighbor Discovery Solicitation Message", Reference: "[RFC3122]"},
+	{Type: 142, Name: "ind-neighbor-advert", Description: "Inverse Neighbor Discovery Advertisement Message", Reference: "[RFC3122]"},
+	{Type: 143, Name: "mld2-listener-report", Description: "Version 2 Multicast Listener Report", Reference: "[RFC3810]"},
+	{Type: 144, Name: "haad-request", Description: "Home Agent Address Discovery Request Message", Reference: "[RFC6275]"},
+	{Type: 145, Name: "haad-reply", Description: "Home Agent Address Discovery Reply Message", Reference: "[RFC6275]"},
+	{Type: 146, Name: "mobile-prefix-solicit", Description: "Mobile Prefix Solicitation", Reference: "[RFC6275]"},
+	{Type: 147, Name: "mobile-prefix-advert", Description: "Mobile Prefix Advertisement", Reference: "[RFC6275]"},
+	{Type: 148, Name: "certification-path-solicit", Description: "Certification Path Solicitation Message", Reference: "[RFC3971]"},
+	{Type: 149, Name: "certification-path-advert", Description: "Certification Path Advertisement Message", Reference: "[RFC3971]"},
+	{Type: 150, Name: "experimental-mobility", Description: "ICMP messages utilized by experimental mobility protocols such as Seamoby", Reference: "[RFC4065]"},
+	{Type: 151, Name: "multicast-router-advert", Description: "Multicast Router Advertisement", Reference: "[RFC4286]"},
+	{Type: 152, Name: "multicast-router-solicit", Description: "Multicast Router Solicitation", Reference: "[RFC4286]"},
+	{Type: 153, Name: "multicast-router-termination", Description: "Multicast Router Termination", Reference: "[RFC4286]"},
+	{Type: 154, Name: "fmipv6", Description: "FMIPv6 Messages", Reference: "[RFC5568]"},
+	{Type: 155, Name: "rpl-control", Description: "RPL Control Message", Reference: "[RFC6550]"},
+	{Type: 156, Name: "ilnpv6-locator-update", Description: "ILNPv6 Locator Update Message", Reference: "[RFC6743]"},
+	{Type: 157, Name: "duplicate-address-request", Description: "Duplicate Address Request", Reference: "[RFC6775]"},
+	{Type: 158, Name: "duplicate-address-confirmation", Description: "Duplicate Address Confirmation", Reference: "[RFC6775]"},
+	{Type: 159, Name: "mpl-control", Description: "MPL Control Message", Reference: "[RFC7731]"},
+	{Type: 160, Name: "extended-echo-request", Description: "Extended Echo Request", Reference: "[RFC8335]", Codes: []ICMPCode{
+		{Code: 0, Name: "no-error", Description: "No Error"},
+	}},
+	{Type: 161, Name: "extended-echo-reply", Description: "Extended Echo Reply", Reference: "[RFC8335]", Codes: []ICMPCode{
+		{Code: 0, Name: "no-error", Description: "No Error"},
+		{Code: 1, Name: "malformed-query", Description: "Malformed Query"},
+		{Code: 2, Name: "no-such-interface", Description: "No Such Interface"},
+		{Code: 3, Name: "no-such-table-entry", Description: "No Such Table Entry"},
+		{Code: 4, Name: "multiple-interfaces-satisfy-query", Description: "Multiple Interfaces Satisfy Query"},
+	}},
+}
@@ -0,0 +1,81 @@
+// Code generated by go generate. DO NOT EDIT.
+
+// Generated from local iproute2 files in /etc/iproute2
+// At 2026-10-16T14:16:27Z
+// File rt_protos, rt_tables, rt_scopes, rt_realms, rt_dsfield
+
+package netdb
+
+var BuiltinRouteProtocols []RouteName = builtinRouteProtocols
+var builtinRouteProtocols = []RouteName{
+		{ Name: "unspec", Number: 0, Comment: "" },
+		{ Name: "redirect", Number: 1, Comment: "" },
+		{ Name: "kernel", Number: 2, Comment: "" },
+		{ Name: "boot", Number: 3, Comment: "" },
+		{ Name: "static", Number: 4, Comment: "" },
+		{ Name: "gated", Number: 8, Comment: "" },
+		{ Name: "ra", Number: 9, Comment: "" },
+		{ Name: "mrt", Number: 10, Comment: "" },
+		{ Name: "zebra", Number: 11, Comment: "" },
+		{ Name: "bird", Number: 12, Comment: "" },
+		{ Name: "dnrouted", Number: 13, Comment: "" },
+		{ Name: "xorp", Number: 14, Comment: "" },
+		{ Name: "ntk", Number: 15, Comment: "" },
+		{ Name: "dhcp", Number: 16, Comment: "" },
+		{ Name: "keepalived", Number: 18, Comment: "" },
+		{ Name: "babel", Number: 42, Comment: "" },
+		{ Name: "openr", Number: 99, Comment: "" },
+		{ Name: "bgp", Number: 186, Comment: "" },
+		{ Name: "isis", Number: 187, Comment: "" },
+		{ Name: "ospf", Number: 188, Comment: "" },
+		{ Name: "rip", Number: 189, Comment: "" },
+		{ Name: "eigrp", Number: 192, Comment: "" },
+	}
+
+var BuiltinRouteTables []RouteName = builtinRouteTables
+var builtinRouteTables = []RouteName{
+		{ Name: "local", Number: 255, Comment: "" },
+		{ Name: "main", Number: 254, Comment: "" },
+		{ Name: "default", Number: 253, Comment: "" },
+		{ Name: "unspec", Number: 0, Comment: "" },
+	}
+
+var BuiltinRouteScopes []RouteName = builtinRouteScopes
+var builtinRouteScopes = []RouteName{
+		{ Name: "global", Number: 0, Comment: "" },
+		{ Name: "nowhere", Number: 255, Comment: "" },
+		{ Name: "host", Number: 254, Comment: "" },
+		{ Name: "link", Number: 253, Comment: "" },
+		{ Name: "site", Number: 200, Comment: "" },
+	}
+
+var BuiltinRouteRealms []RouteName = builtinRouteRealms
+var builtinRouteRealms = []RouteName{
+		{ Name: "cosmos", Number: 0, Comment: "" },
+	}
+
+var BuiltinRouteDSFields []RouteName = builtinRouteDSFields
+var builtinRouteDSFields = []RouteName{
+		{ Name: "default", Number: 0, Comment: "" },
+		{ Name: "AF11", Number: 40, Comment: "" },
+		{ Name: "AF12", Number: 48, Comment: "" },
+		{ Name: "AF13", Number: 56, Comment: "" },
+		{ Name: "AF21", Number: 72, Comment: "" },
+		{ Name: "AF22", Number: 80, Comment: "" },
+		{ Name: "AF23", Number: 88, Comment: "" },
+		{ Name: "AF31", Number: 104, Comment: "" },
+		{ Name: "AF32", Number: 112, Comment: "" },
+		{ Name: "AF33", Number: 120, Comment: "" },
+		{ Name: "AF41", Number: 136, Comment: "" },
+		{ Name: "AF42", Number: 144, Comment: "" },
+		{ Name: "AF43", Number: 152, Comment: "" },
+		{ Name: "CS1", Number: 32, Comment: "" },
+		{ Name: "CS2", Number: 64, Comment: "" },
+		{ Name: "CS3", Number: 96, Comment: "" },
+		{ Name: "CS4", Number: 128, Comment: "" },
+		{ Name: "CS5", Number: 160, Comment: "" },
+		{ Name: "CS6", Number: 192, Comment: "" },
+		{ Name: "CS7", Number: 224, Comment: "" },
+		{ Name: "EF", Number: 184, Comment: "" },
+	}
+
@@ -0,0 +1,129 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+// BuiltinIPOptions lists the IPv4 options, compiled from the IANA "IP Option
+// Numbers" registry at https://www.iana.org/assignments/ip-parameters. The
+// option numbers are the complete option type octets, including the copied
+// flag and option class.
+var BuiltinIPOptions = []HeaderOption{
+	{Name: "EOOL", Number: 0, Description: "End of Options List", Reference: "[RFC791]"},
+	{Name: "NOP", Number: 1, Description: "No Operation", Reference: "[RFC791]"},
+	{Name: "RR", Number: 7, Description: "Record Route", Reference: "[RFC791]"},
+	{Name: "ZSU", Number: 10, Description: "Experimental Measurement", Reference: "[ZSu]"},
+	{Name: "MTUP", Number: 11, Description: "MTU Probe", Reference: "[RFC1063][RFC1191]"},
+	{Name: "MTUR", Number: 12, Description: "MTU Reply", Reference: "[RFC1063][RFC1191]"},
+	{Name: "ENCODE", Number: 15, Reference: "[VerSteeg][RFC6814]"},
+	{Name: "QS", Number: 25, Description: "Quick-Start", Reference: "[RFC4782]"},
+	{Name: "EXP", Number: 30, Description: "RFC3692-style Experiment", Reference: "[RFC4727]"},
+	{Name: "TS", Number: 68, Description: "Time Stamp", Reference: "[RFC791]"},
+	{Name: "TR", Number: 82, Description: "Traceroute", Reference: "[RFC1393][RFC6814]"},
+	{Name: "EXP", Number: 94, Description: "RFC3692-style Experiment", Reference: "[RFC4727]"},
+	{Name: "SEC", Number: 130, Description: "Security (RIPSO)", Reference: "[RFC1108]"},
+	{Name: "LSR", Number: 131, Aliases: []string{"LSRR"}, Description: "Loose Source Route", Reference: "[RFC791]"},
+	{Name: "E-SEC", Number: 133, Description: "Extended Security", Reference: "[RFC1108]"},
+	{Name: "CIPSO", Number: 134, Description: "Commercial Security", Reference: "[draft-ietf-cipso-ipsecurity-01]"},
+	{Name: "SID", Number: 136, Description: "Stream ID", Reference: "[RFC791][RFC6814]"},
+	{Name: "SSR", Number: 137, Aliases: []string{"SSRR"}, Description: "Strict Source Route", Reference: "[RFC791]"},
+	{Name: "VISA", Number: 142, Description: "Experimental Access Control", Reference: "[Deborah_Estrin][RFC6814]"},
+	{Name: "IMITD", Number: 144, Description: "IMI Traffic Descriptor", Reference: "[Lee][RFC6814]"},
+	{Name: "EIP", Number: 145, Description: "Extended Internet Protocol", Reference: "[RFC1385][RFC6814]"},
+	{Name: "ADDEXT", Number: 147, Description: "Address Extension", Reference: "[Ullmann IPv7][RFC6814]"},
+	{Name: "RTRALT", Number: 148, Aliases: []string{"RA"}, Description: "Router Alert", Reference: "[RFC2113]"},
+	{Name: "SDB", Number: 149, Description: "Selective Directed Broadcast", Reference: "[Charles_Bud_Graff][RFC6814]"},
+	{Name: "DPS", Number: 151, Description: "Dynamic Packet State", Reference: "[Andy_Malis][RFC6814]"},
+	{Name: "UMP", Number: 152, Description: "Upstream Multicast Pkt.", Reference: "[Dino_Farinacci][RFC6814]"},
+	{Name: "EXP", Number: 158, Description: "RFC3692-style Experiment", Reference: "[RFC4727]"},
+	{Name: "FINN", Number: 205, Description: "Experimental Flow Control", Reference: "[Greg_Finn]"},
+	{Name: "EXP", Number: 222, Description: "RFC3692-style Experiment", Reference: "[RFC4727]"},
+}
+
+// BuiltinIPv6Options lists the IPv6 Hop-by-Hop and Destination options,
+// compiled from the IANA "Destination Options and Hop-by-Hop Options"
+// registry at https://www.iana.org/assignments/ipv6-parameters. The option
+// numbers are the complete option type octets, including the action and
+// change bits.
+var BuiltinIPv6Options = []HeaderOption{
+	{Name: "Pad1", Number: 0x00, Description: "Pad1", Reference: "[RFC8200]"},
+	{Name: "PadN", Number: 0x01, Description: "PadN", Reference: "[RFC8200]"},
+	{Name: "TEL", Number: 0x04, Description: "Tunnel Encapsulation Limit", Reference: "[RFC2473]"},
+	{Name: "RTRALT", Number: 0x05, Aliases: []string{"RA"}, Description: "Router Alert", Reference: "[RFC2711]"},
+	{Name: "CALIPSO", Number: 0x07, Description: "CALIPSO", Reference: "[RFC5570]"},
+	{Name: "SMF_DPD", Number: 0x08, Description: "SMF_DPD", Reference: "[RFC6621]"},
+	{Name: "PDM", Number: 0x0f, Description: "Performance and Diagnostic Metrics", Reference: "[RFC8250]"},
+	{Name: "IOAM", Number: 0x11, Description: "IOAM Destination Option and IOAM Hop-by-Hop Option", Reference: "[RFC9486]"},
+	{Name: "AltMark", Number: 0x12, Description: "Alternate Marking", Reference: "[RFC9343]"},
+	{Name: "EXP", Number: 0x1e, Description: "RFC3692-style Experiment", Reference: "[RFC4727]"},
+	{Name: "RPL", Number: 0x23, Description: "RPL Option", Reference: "[RFC9008]"},
+	{Name: "QS", Number: 0x26, Description: "Quick-Start", Reference: "[RFC4782]"},
+	{Name: "MinPMTU", Number: 0x30, Description: "Minimum Path MTU Hop-by-Hop Option", Reference: "[RFC9268]"},
+	{Name: "IOAM", Number: 0x31, Description: "IOAM Destination Option and IOAM Hop-by-Hop Option", Reference: "[RFC9486]"},
+	{Name: "EXP", Number: 0x3e, Description: "RFC3692-style Experiment", Reference: "[RFC4727]"},
+	{Name: "MPL-deprecated", Number: 0x4d, Description: "Deprecated", Reference: "[RFC7731]"},
+	{Name: "EXP", Number: 0x5e, Description: "RFC3692-style Experiment", Reference: "[RFC4727]"},
+	{Name: "RPL-deprecated", Number: 0x63, Description: "RPL Option (DEPRECATED)", Reference: "[RFC6553][RFC9008]"},
+	{Name: "MPL", Number: 0x6d, Description: "MPL Option", Reference: "[RFC7731]"},
+	{Name: "EXP", Number: 0x7e, Description: "RFC3692-style Experiment", Reference: "[RFC4727]"},
+	{Name: "EID", Number: 0x8a, Description: "Endpoint Identification (DEPRECATED)", Reference: "[Charles_Lynn]"},
+	{Name: "ILNP-Nonce", Number: 0x8b, Description: "ILNP Nonce", Reference: "[RFC6744]"},
+	{Name: "LIO", Number: 0x8c, Description: "Line-Identification Option", Reference: "[RFC6788]"},
+	{Name: "EXP", Number: 0x9e, Description: "RFC3692-style Experiment", Reference: "[RFC4727]"},
+	{Name: "EXP", Number: 0xbe, Description: "RFC3692-style Experiment", Reference: "[RFC4727]"},
+	{Name: "Jumbo", Number: 0xc2, Description: "Jumbo Payload", Reference: "[RFC2675]"},
+	{Name: "HAO", Number: 0xc9, Description: "Home Address", Reference: "[RFC6275]"},
+	{Name: "EXP", Number: 0xde, Description: "RFC3692-style Experiment", Reference: "[RFC4727]"},
+	{Name: "IP_DFF", Number: 0xee, Description: "IP_DFF", Reference: "[RFC6971]"},
+	{Name: "EXP", Number: 0xfe, Description: "RFC3692-style Experiment", Reference: "[RFC4727]"},
+}
+
+// BuiltinTCPOptions lists the TCP option kinds, compiled from the IANA "TCP
+// Option Kind Numbers" registry at
+// https://www.iana.org/assignments/tcp-parameters.
+var BuiltinTCPOptions = []HeaderOption{
+	{Name: "EOL", Number: 0, Description: "End of Option List", Reference: "[RFC9293]"},
+	{Name: "NOP", Number: 1, Description: "No-Operation", Reference: "[RFC9293]"},
+	{Name: "MSS", Number: 2, Description: "Maximum Segment Size", Reference: "[RFC9293]"},
+	{Name: "WS", Number: 3, Aliases: []string{"WSCALE"}, Description: "Window Scale", Reference: "[RFC7323]"},
+	{Name: "SACK-PERM", Number: 4, Description: "SACK Permitted", Reference: "[RFC2018]"},
+	{Name: "SACK", Number: 5, Description: "SACK", Reference: "[RFC2018]"},
+	{Name: "ECHO", Number: 6, Description: "Echo (obsoleted by option 8)", Reference: "[RFC1072][RFC6247]"},
+	{Name: "ECHO-REPLY", Number: 7, Description: "Echo Reply (obsoleted by option 8)", Reference: "[RFC1072][RFC6247]"},
+	{Name: "TS", Number: 8, Aliases: []string{"TIMESTAMP"}, Description: "Timestamps", Reference: "[RFC7323]"},
+	{Name: "POC-PERM", Number: 9, Description: "Partial Order Connection Permitted (obsolete)", Reference: "[RFC1693][RFC6247]"},
+	{Name: "POC-SP", Number: 10, Description: "Partial Order Service Profile (obsolete)", Reference: "[RFC1693][RFC6247]"},
+	{Name: "CC", Number: 11, Description: "CC (obsolete)", Reference: "[RFC1644][RFC6247]"},
+	{Name: "CC.NEW", Number: 12, Description: "CC.NEW (obsolete)", Reference: "[RFC1644][RFC6247]"},
+	{Name: "CC.ECHO", Number: 13, Description: "CC.ECHO (obsolete)", Reference: "[RFC1644][RFC6247]"},
+	{Name: "ALT-CHK-REQ", Number: 14, Description: "TCP Alternate Checksum Request (obsolete)", Reference: "[RFC1146][RFC6247]"},
+	{Name: "ALT-CHK-DATA", Number: 15, Description: "TCP Alternate Checksum Data (obsolete)", Reference: "[RFC1146][RFC6247]"},
+	{Name: "SKEETER", Number: 16, Description: "Skeeter", Reference: "[Stev_Knowles]"},
+	{Name: "BUBBA", Number: 17, Description: "Bubba", Reference: "[Stev_Knowles]"},
+	{Name: "TRAILER-CHK", Number: 18, Description: "Trailer Checksum Option", Reference: "[Subbu_Subramaniam][Monroe_Bridges]"},
+	{Name: "MD5", Number: 19, Description: "MD5 Signature Option (obsoleted by option 29)", Reference: "[RFC2385]"},
+	{Name: "SCPS", Number: 20, Description: "SCPS Capabilities", Reference: "[Keith_Scott]"},
+	{Name: "SNACK", Number: 21, Description: "Selective Negative Acknowledgements", Reference: "[Keith_Scott]"},
+	{Name: "REC-BOUND", Number: 22, Description: "Record Boundaries", Reference: "[Keith_Scott]"},
+	{Name: "CORRUPTION", Number: 23, Description: "Corruption experienced", Reference: "[Keith_Scott]"},
+	{Name: "SNAP", Number: 24, Description: "SNAP", Reference: "[Vladimir_Sukonnik]"},
+	{Name: "COMP-FILTER", Number: 26, Description: "TCP Compression Filter", Reference: "[Steve_Bellovin]"},
+	{Name: "QS", Number: 27, Description: "Quick-Start Response", Reference: "[RFC4782]"},
+	{Name: "UTO", Number: 28, Description: "User Timeout Option", Reference: "[RFC5482]"},
+	{Name: "AO", Number: 29, Aliases: []string{"TCP-AO"}, Description: "TCP Authentication Option (TCP-AO)", Reference: "[RFC5925]"},
+	{Name: "MPTCP", Number: 30, Description: "Multipath TCP (MPTCP)", Reference: "[RFC8684]"},
+	{Name: "TFO", Number: 34, Description: "TCP Fast Open Cookie", Reference: "[RFC7413]"},
+	{Name: "ENO", Number: 69, Aliases: []string{"TCP-ENO"}, Description: "Encryption Negotiation (TCP-ENO)", Reference: "[RFC8547]"},
+	{Name: "EXP1", Number: 253, Description: "RFC3692-style Experiment 1", Reference: "[RFC4727]"},
+	{Name: "EXP2", Number: 254, Description: "RFC3692-style Experiment 2", Reference: "[RFC4727]"},
+}
@@ -0,0 +1,85 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"bytes"
+	"compress/gzip"
+)
+
+// BuiltinOUIs lists a small, hand-picked set of MAC address blocks commonly
+// seen in data centers, virtualization, and lab networks, with short vendor
+// names as found in Wireshark's "manuf" file. It additionally lists the
+// well-known multicast and broadcast blocks not assigned in the IEEE
+// registries.
+//
+// The builtin OUI index consists of the embedded IEEE MA-L, MA-M, and MA-S
+// registries, with BuiltinOUIs merged on top.
+var BuiltinOUIs = []OUI{
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x00, 0x0c}, Bits: 24}, Name: "Cisco", Organization: "Cisco Systems, Inc", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x00, 0x5e}, Bits: 24}, Name: "ICANN", Organization: "ICANN, IANA Department", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x02, 0xc9}, Bits: 24}, Name: "Mellanox", Organization: "Mellanox Technologies, Inc.", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x03, 0x93}, Bits: 24}, Name: "Apple", Organization: "Apple, Inc.", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x03, 0xff}, Bits: 24}, Name: "Microsoft", Organization: "Microsoft Corporation", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x05, 0x69}, Bits: 24}, Name: "VMware", Organization: "VMware, Inc.", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x05, 0x85}, Bits: 24}, Name: "Juniper", Organization: "Juniper Networks", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x0c, 0x29}, Bits: 24}, Name: "VMware", Organization: "VMware, Inc.", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x0d, 0x3a}, Bits: 24}, Name: "Microsoft", Organization: "Microsoft Corp.", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x10, 0x18}, Bits: 24}, Name: "Broadcom", Organization: "Broadcom", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x14, 0x22}, Bits: 24}, Name: "Dell", Organization: "Dell Inc.", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x15, 0x5d}, Bits: 24}, Name: "Microsoft", Organization: "Microsoft Corporation", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x16, 0x3e}, Bits: 24}, Name: "Xensource", Organization: "Xensource, Inc.", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x1a, 0x11}, Bits: 24}, Name: "Google", Organization: "Google, Inc.", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x1b, 0x21}, Bits: 24}, Name: "Intel", Organization: "Intel Corporate", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x1c, 0x14}, Bits: 24}, Name: "VMware", Organization: "VMware, Inc.", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x1c, 0x42}, Bits: 24}, Name: "Parallels", Organization: "Parallels, Inc.", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x1c, 0x73}, Bits: 24}, Name: "Arista", Organization: "Arista Networks", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x25, 0x90}, Bits: 24}, Name: "Supermicro", Organization: "Super Micro Computer, Inc.", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0x50, 0x56}, Bits: 24}, Name: "VMware", Organization: "VMware, Inc.", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0xa0, 0xc9}, Bits: 24}, Name: "Intel", Organization: "Intel Corporation", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x00, 0xe0, 0x4c}, Bits: 24}, Name: "Realtek", Organization: "Realtek Semiconductor Corp.", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x08, 0x00, 0x27}, Bits: 24}, Name: "PCSSystemtec", Organization: "PCS Systemtechnik GmbH", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x3c, 0x5a, 0xb4}, Bits: 24}, Name: "Google", Organization: "Google, Inc.", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x3c, 0xfd, 0xfe}, Bits: 24}, Name: "Intel", Organization: "Intel Corporate", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0xb8, 0x27, 0xeb}, Bits: 24}, Name: "RaspberryPi", Organization: "Raspberry Pi Foundation", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0xdc, 0xa6, 0x32}, Bits: 24}, Name: "RaspberryPi", Organization: "Raspberry Pi Trading Ltd", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0xe4, 0x5f, 0x01}, Bits: 24}, Name: "RaspberryPi", Organization: "Raspberry Pi Trading Ltd", Registry: "MA-L"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x01, 0x00, 0x5e}, Bits: 25}, Name: "IPv4mcast", Organization: "IPv4 multicast"},
+	{Prefix: MACPrefix{Addr: [6]byte{0x33, 0x33}, Bits: 16}, Name: "IPv6mcast", Organization: "IPv6 multicast"},
+	{Prefix: MACPrefix{Addr: [6]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, Bits: 48}, Name: "Broadcast", Organization: "Broadcast"},
+}
+
+// builtinOUIs returns the MAC address blocks from the embedded IEEE
+// registries, followed by the BuiltinOUIs.
+func builtinOUIs() OUIIndex {
+	ouis, err := decodeIEEEOUIs(builtinIEEEOUIsCSV)
+	if err != nil {
+		panic("corrupt builtin IEEE registries table: " + err.Error())
+	}
+	i := NewOUIIndex(ouis)
+	i.Merge(BuiltinOUIs)
+	return i
+}
+
+// decodeIEEEOUIs returns the MAC address blocks from the specified
+// gzip-compressed IEEE registry CSV table.
+func decodeIEEEOUIs(table []byte) ([]OUI, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(table))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return ParseIEEEOUICSV(zr)
+}
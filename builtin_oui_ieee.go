@@ -0,0 +1,27 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// This file gets replaced by "make refresh-oui" with the generated file
+// embedding the IEEE registries; until then, the embedded table only consists
+// of the CSV header.
+
+package netdb
+
+import _ "embed"
+
+// builtinIEEEOUIsCSV is the gzip-compressed CSV table of the MAC address
+// blocks assigned in the IEEE MA-L, MA-M, and MA-S registries.
+//
+//go:embed builtin_oui_ieee.csv.gz
+var builtinIEEEOUIsCSV []byte
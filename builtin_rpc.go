@@ -0,0 +1,50 @@
+// Code generated by go generate. DO NOT EDIT.
+
+// Generated from local netbase file /etc/rpc
+// At 2026-10-16T14:10:07Z
+// File etc/rpc
+
+package netdb
+
+var BuiltinRPCPrograms []RPCProgram = builtinRPCPrograms
+var builtinRPCPrograms = []RPCProgram{
+		{ Name: "portmapper", Number: 100000, Comment: "", Aliases: []string{"portmap","sunrpc","rpcbind",} },
+		{ Name: "rstatd", Number: 100001, Comment: "", Aliases: []string{"rstat","rstat_svc","rup","perfmeter",} },
+		{ Name: "rusersd", Number: 100002, Comment: "", Aliases: []string{"rusers",} },
+		{ Name: "nfs", Number: 100003, Comment: "", Aliases: []string{"nfsprog",} },
+		{ Name: "ypserv", Number: 100004, Comment: "", Aliases: []string{"ypprog",} },
+		{ Name: "mountd", Number: 100005, Comment: "", Aliases: []string{"mount","showmount",} },
+		{ Name: "ypbind", Number: 100007, Comment: "", Aliases: []string{} },
+		{ Name: "walld", Number: 100008, Comment: "", Aliases: []string{"rwall","shutdown",} },
+		{ Name: "yppasswdd", Number: 100009, Comment: "", Aliases: []string{"yppasswd",} },
+		{ Name: "etherstatd", Number: 100010, Comment: "", Aliases: []string{"etherstat",} },
+		{ Name: "rquotad", Number: 100011, Comment: "", Aliases: []string{"rquotaprog","quota","rquota",} },
+		{ Name: "sprayd", Number: 100012, Comment: "", Aliases: []string{"spray",} },
+		{ Name: "3270_mapper", Number: 100013, Comment: "", Aliases: []string{} },
+		{ Name: "rje_mapper", Number: 100014, Comment: "", Aliases: []string{} },
+		{ Name: "selection_svc", Number: 100015, Comment: "", Aliases: []string{"selnsvc",} },
+		{ Name: "database_svc", Number: 100016, Comment: "", Aliases: []string{} },
+		{ Name: "rexd", Number: 100017, Comment: "", Aliases: []string{"rex",} },
+		{ Name: "alis", Number: 100018, Comment: "", Aliases: []string{} },
+		{ Name: "sched", Number: 100019, Comment: "", Aliases: []string{} },
+		{ Name: "llockmgr", Number: 100020, Comment: "", Aliases: []string{} },
+		{ Name: "nlockmgr", Number: 100021, Comment: "", Aliases: []string{} },
+		{ Name: "x25.inr", Number: 100022, Comment: "", Aliases: []string{} },
+		{ Name: "statmon", Number: 100023, Comment: "", Aliases: []string{} },
+		{ Name: "status", Number: 100024, Comment: "", Aliases: []string{} },
+		{ Name: "bootparam", Number: 100026, Comment: "", Aliases: []string{} },
+		{ Name: "ypupdated", Number: 100028, Comment: "", Aliases: []string{"ypupdate",} },
+		{ Name: "keyserv", Number: 100029, Comment: "", Aliases: []string{"keyserver",} },
+		{ Name: "tfsd", Number: 100037, Comment: "", Aliases: []string{} },
+		{ Name: "nsed", Number: 100038, Comment: "", Aliases: []string{} },
+		{ Name: "nsemntd", Number: 100039, Comment: "", Aliases: []string{} },
+		{ Name: "ypxfrd", Number: 100069, Comment: "", Aliases: []string{} },
+		{ Name: "nfs_acl", Number: 100227, Comment: "", Aliases: []string{} },
+		{ Name: "pcnfsd", Number: 150001, Comment: "", Aliases: []string{} },
+		{ Name: "amd", Number: 300019, Comment: "", Aliases: []string{"amq",} },
+		{ Name: "sgi_fam", Number: 391002, Comment: "", Aliases: []string{} },
+		{ Name: "ugidd", Number: 545580417, Comment: "", Aliases: []string{} },
+		{ Name: "fypxfrd", Number: 600100069, Comment: "", Aliases: []string{"freebsd-ypxfrd",} },
+		{ Name: "bwnfsd", Number: 788585389, Comment: "", Aliases: []string{} },
+	}
+	
\ No newline at end of file
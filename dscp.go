@@ -0,0 +1,228 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"iter"
+	"slices"
+)
+
+// Codepoint describes a DSCP (Differentiated Services Codepoint) or ECN
+// (Explicit Congestion Notification) codepoint of the IPv4 TOS and IPv6
+// Traffic Class octets, such as the DSCP "EF" with number 46 or the ECN "CE"
+// with number 3.
+type Codepoint struct {
+	Name        string   // Codepoint name, such as "EF" or "AF41".
+	Number      uint8    // Codepoint value: 6 bits for DSCPs, 2 bits for ECNs.
+	Aliases     []string // List of aliases.
+	Description string   // Codepoint description.
+	Reference   string   // Reference(s), such as "[RFC3246]".
+}
+
+// CodepointIndex indexes the known DSCP or ECN codepoints by either name
+// (native as well as aliases) and by number.
+//
+// When multiple codepoints share the same name or number, the Precedence rule
+// decides which codepoint becomes the primary one. The Precedence rule needs
+// to be set before merging any codepoints.
+type CodepointIndex struct {
+	Names      map[string]*Codepoint // Index by codepoint name, including aliases.
+	Numbers    map[uint8]*Codepoint  // Index by codepoint number.
+	Precedence Precedence            // Rule for picking the primary codepoint.
+
+	entries []*Codepoint // all merged codepoints in definition order.
+}
+
+// NewCodepointIndex returns a CodepointIndex object initialized with the
+// specified codepoints.
+func NewCodepointIndex(codepoints []Codepoint) CodepointIndex {
+	i := CodepointIndex{
+		Names:   map[string]*Codepoint{},
+		Numbers: map[uint8]*Codepoint{},
+	}
+	i.Merge(codepoints)
+	return i
+}
+
+// Merge a list of Codepoint descriptions into the current codepoints index,
+// potentially overriding existing entries in the index in case of duplicates,
+// depending on the index's Precedence rule.
+func (i *CodepointIndex) Merge(codepoints []Codepoint) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for idx, codepoint := range codepoints {
+		setPrimary(i.Names, codepoint.Name, &codepoints[idx], firstWins)
+		for _, alias := range codepoint.Aliases {
+			setPrimary(i.Names, alias, &codepoints[idx], firstWins)
+		}
+		setPrimary(i.Numbers, codepoint.Number, &codepoints[idx], firstWins)
+		i.entries = append(i.entries, &codepoints[idx])
+	}
+}
+
+// MergeIndex merges another CodepointIndex into the current index, potentially
+// overriding existing entries in case of duplicates, depending on the index's
+// Precedence rule.
+func (i *CodepointIndex) MergeIndex(ci CodepointIndex) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for name, codepoint := range ci.Names {
+		setPrimary(i.Names, name, codepoint, firstWins)
+	}
+	for number, codepoint := range ci.Numbers {
+		setPrimary(i.Numbers, number, codepoint, firstWins)
+	}
+	i.entries = append(i.entries, ci.entries...)
+}
+
+// init initializes the index maps, if not already done.
+func (i *CodepointIndex) init() {
+	if i.Names == nil {
+		i.Names = map[string]*Codepoint{}
+	}
+	if i.Numbers == nil {
+		i.Numbers = map[uint8]*Codepoint{}
+	}
+}
+
+// ByName returns the Codepoint for the specified (alias) name, or nil if not
+// found.
+func (i *CodepointIndex) ByName(name string) *Codepoint {
+	return i.Names[name]
+}
+
+// ByNumber returns the Codepoint for the specified codepoint number, or nil if
+// not found.
+func (i *CodepointIndex) ByNumber(number uint8) *Codepoint {
+	return i.Numbers[number]
+}
+
+// AllByName returns all codepoints with the specified (alias) name in the
+// order of their original definitions, including codepoints overridden by
+// later merges.
+func (i *CodepointIndex) AllByName(name string) []*Codepoint {
+	var codepoints []*Codepoint
+	for codepoint := range i.All() {
+		if codepoint.Name == name || slices.Contains(codepoint.Aliases, name) {
+			codepoints = append(codepoints, codepoint)
+		}
+	}
+	return codepoints
+}
+
+// AllByNumber returns all codepoints with the specified codepoint number in
+// the order of their original definitions, including codepoints overridden by
+// later merges.
+func (i *CodepointIndex) AllByNumber(number uint8) []*Codepoint {
+	var codepoints []*Codepoint
+	for codepoint := range i.All() {
+		if codepoint.Number == number {
+			codepoints = append(codepoints, codepoint)
+		}
+	}
+	return codepoints
+}
+
+// All returns an iterator over all codepoints merged into this index, in the
+// order of their original definitions. Each codepoint is yielded only once,
+// regardless of its aliases, and including codepoints that have been
+// overridden by later merges.
+func (i *CodepointIndex) All() iter.Seq[*Codepoint] {
+	return allOf(i.entries)
+}
+
+// SplitTOS splits an IPv4 TOS or IPv6 Traffic Class octet into its DSCP (upper
+// six bits) and ECN (lower two bits) parts.
+func SplitTOS(tos uint8) (dscp uint8, ecn uint8) {
+	return tos >> 2, tos & 0x03
+}
+
+// JoinTOS returns the IPv4 TOS or IPv6 Traffic Class octet for the specified
+// DSCP and ECN parts. Excess DSCP and ECN bits are ignored.
+func JoinTOS(dscp uint8, ecn uint8) uint8 {
+	return dscp<<2 | ecn&0x03
+}
+
+// TOSCodepoints splits an IPv4 TOS or IPv6 Traffic Class octet into its DSCP
+// and ECN parts and returns their Codepoint details. If either part is not
+// defined, then its returned Codepoint is nil.
+func TOSCodepoints(tos uint8) (dscp *Codepoint, ecn *Codepoint) {
+	d, e := SplitTOS(tos)
+	return DSCPByNumber(d), ECNByNumber(e)
+}
+
+// DSCPByName returns the DSCP Codepoint details for the specified (native or
+// aliased) name, such as "EF", or nil if not defined.
+func DSCPByName(name string) *Codepoint {
+	idx := defaultDSCPs.rlock()
+	defer defaultDSCPs.runlock()
+	return idx.ByName(name)
+}
+
+// DSCPByNumber returns the DSCP Codepoint details for the specified (6 bit)
+// DSCP number, or nil if not defined.
+func DSCPByNumber(number uint8) *Codepoint {
+	idx := defaultDSCPs.rlock()
+	defer defaultDSCPs.runlock()
+	return idx.ByNumber(number)
+}
+
+// ECNByName returns the ECN Codepoint details for the specified (native or
+// aliased) name, such as "CE", or nil if not defined.
+func ECNByName(name string) *Codepoint {
+	idx := defaultECNs.rlock()
+	defer defaultECNs.runlock()
+	return idx.ByName(name)
+}
+
+// ECNByNumber returns the ECN Codepoint details for the specified (2 bit) ECN
+// number, or nil if not defined.
+func ECNByNumber(number uint8) *Codepoint {
+	idx := defaultECNs.rlock()
+	defer defaultECNs.runlock()
+	return idx.ByNumber(number)
+}
+
+// SetDSCPs atomically replaces the DSCPs index with the specified index. It is
+// safe to call SetDSCPs while lookups are in flight in other goroutines; these
+// lookups finish on the old index.
+func SetDSCPs(i CodepointIndex) {
+	defaultDSCPs.set(i)
+}
+
+// SetECNs atomically replaces the ECNs index with the specified index. It is
+// safe to call SetECNs while lookups are in flight in other goroutines; these
+// lookups finish on the old index.
+func SetECNs(i CodepointIndex) {
+	defaultECNs.set(i)
+}
+
+// DSCPs is the index of DSCP names and numbers. If left to the zero value,
+// then it will be automatically initialized with the builtin definitions upon
+// first use of DSCPByName, DSCPByNumber, or TOSCodepoints. This initialization
+// is goroutine-safe.
+//
+// Directly assigning to DSCPs or merging into it is not safe while lookups are
+// in flight; use SetDSCPs instead.
+var DSCPs CodepointIndex
+
+// ECNs is the index of ECN names and numbers. If left to the zero value, then
+// it will be automatically initialized with the builtin definitions upon first
+// use of ECNByName, ECNByNumber, or TOSCodepoints. This initialization is
+// goroutine-safe.
+//
+// Directly assigning to ECNs or merging into it is not safe while lookups are
+// in flight; use SetECNs instead.
+var ECNs CodepointIndex
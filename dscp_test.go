@@ -0,0 +1,89 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("DSCP and ECN codepoints", func() {
+
+	BeforeEach(func() {
+		reset := func() {
+			DSCPs = CodepointIndex{}
+			ECNs = CodepointIndex{}
+		}
+		reset()
+		DeferCleanup(reset)
+	})
+
+	It("has unique builtin numbers and names", func() {
+		for _, codepoints := range [][]Codepoint{BuiltinDSCPs, BuiltinECNs} {
+			idx := NewCodepointIndex(codepoints)
+			Expect(idx.Numbers).To(HaveLen(len(codepoints)))
+			for codepoint := range idx.All() {
+				Expect(idx.AllByName(codepoint.Name)).To(HaveLen(1))
+			}
+		}
+		for _, dscp := range BuiltinDSCPs {
+			Expect(dscp.Number).To(BeNumerically("<", 64))
+		}
+	})
+
+	It("looks up DSCPs and ECNs", func() {
+		Expect(DSCPByName("EF")).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Number":    Equal(uint8(46)),
+			"Reference": Equal("[RFC3246]"),
+		})))
+		Expect(DSCPByNumber(34).Name).To(Equal("AF41"))
+		Expect(DSCPByNumber(48).Name).To(Equal("CS6"))
+		Expect(DSCPByName("DF").Name).To(Equal("CS0"))
+		Expect(DSCPByNumber(63)).To(BeNil())
+		Expect(ECNByName("CE").Number).To(Equal(uint8(3)))
+		Expect(ECNByNumber(2).Name).To(Equal("ECT(0)"))
+	})
+
+	It("splits and joins TOS octets", func() {
+		dscp, ecn := SplitTOS(0xb9)
+		Expect(dscp).To(Equal(uint8(46)))
+		Expect(ecn).To(Equal(uint8(1)))
+		Expect(JoinTOS(46, 1)).To(Equal(uint8(0xb9)))
+		Expect(JoinTOS(34, 7)).To(Equal(uint8(0x8b)))
+
+		d, e := TOSCodepoints(0x8b)
+		Expect(d.Name).To(Equal("AF41"))
+		Expect(e.Name).To(Equal("CE"))
+
+		d, e = TOSCodepoints(0xfc)
+		Expect(d).To(BeNil())
+		Expect(e.Name).To(Equal("Not-ECT"))
+	})
+
+	It("merges and replaces", func() {
+		idx := NewCodepointIndex(BuiltinDSCPs)
+		idx.MergeIndex(NewCodepointIndex([]Codepoint{{Name: "NQB", Number: 45}}))
+		Expect(idx.ByNumber(45).Name).To(Equal("NQB"))
+		Expect(idx.AllByNumber(46)).To(HaveLen(1))
+
+		SetDSCPs(idx)
+		SetECNs(NewCodepointIndex(nil))
+		d, e := TOSCodepoints(45 << 2)
+		Expect(d.Name).To(Equal("NQB"))
+		Expect(e).To(BeNil())
+	})
+
+})
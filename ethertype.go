@@ -17,7 +17,9 @@ package netdb
 import (
 	"bufio"
 	"io"
+	"iter"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -38,9 +40,16 @@ type EtherType struct {
 
 // EtherTypeIndex index the known EtherTypes by either name (native as well as
 // aliases) and by number.
+//
+// When multiple EtherTypes share the same name or number, the Precedence rule
+// decides which EtherType becomes the primary one. The Precedence rule needs
+// to be set before merging any EtherTypes.
 type EtherTypeIndex struct {
-	Names   map[string]*EtherType
-	Numbers map[uint16]*EtherType
+	Names      map[string]*EtherType
+	Numbers    map[uint16]*EtherType
+	Precedence Precedence // Rule for picking the primary EtherType.
+
+	entries []*EtherType // all merged EtherTypes in definition order.
 }
 
 // NewEtherTypeIndex returns an EtherTypeIndex object initialized with the
@@ -57,66 +66,153 @@ func NewEtherTypeIndex(ethertypes []EtherType) EtherTypeIndex {
 // LoadEtherTypes returns an EtherTypeIndex object initialized from the
 // defintions in the named file.
 func LoadEtherTypes(name string) (EtherTypeIndex, error) {
+	return LoadEtherTypesWithOptions(name, ParseOptions{})
+}
+
+// LoadEtherTypesWithOptions returns an EtherTypeIndex object initialized from
+// the definitions in the named file, parsing it as specified by the options.
+// If the options don't specify a file name, then the specified name is used.
+// In ParseCollectAll mode, the returned index contains all well-formed
+// definitions, even when a ParseErrors error is returned.
+func LoadEtherTypesWithOptions(name string, opts ParseOptions) (EtherTypeIndex, error) {
 	f, err := os.Open(name)
 	if err != nil {
 		return NewEtherTypeIndex(nil), err
 	}
 	defer f.Close()
-	ethertypes, err := ParseEtherTypes(f)
-	if err != nil {
+	if opts.Filename == "" {
+		opts.Filename = name
+	}
+	ethertypes, err := ParseEtherTypesWithOptions(f, opts)
+	if ethertypes == nil {
 		return NewEtherTypeIndex(nil), err
 	}
-	return NewEtherTypeIndex(ethertypes), nil
+	return NewEtherTypeIndex(ethertypes), err
 }
 
 // Merge a list of EtherType descriptions into the current EtherTypes index,
-// potentially overriding existing entries in the index in case of duplicates.
+// potentially overriding existing entries in the index in case of duplicates,
+// depending on the index's Precedence rule.
 func (i *EtherTypeIndex) Merge(ethertypes []EtherType) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
 	for idx, ethertype := range ethertypes {
-		i.Names[ethertype.Name] = &ethertypes[idx]
+		setPrimary(i.Names, ethertype.Name, &ethertypes[idx], firstWins)
 		for _, alias := range ethertype.Aliases {
-			i.Names[alias] = &ethertypes[idx]
+			setPrimary(i.Names, alias, &ethertypes[idx], firstWins)
 		}
-		i.Numbers[ethertype.Number] = &ethertypes[idx]
+		setPrimary(i.Numbers, ethertype.Number, &ethertypes[idx], firstWins)
+		i.entries = append(i.entries, &ethertypes[idx])
 	}
 }
 
 // MergeIndex merges another EtherTypeIndex into the current index, potentially
-// overriding existing enties in the case of duplicates.
+// overriding existing enties in the case of duplicates, depending on the
+// index's Precedence rule.
 func (i *EtherTypeIndex) MergeIndex(eti EtherTypeIndex) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
 	for name, ethertype := range eti.Names {
-		i.Names[name] = ethertype
+		setPrimary(i.Names, name, ethertype, firstWins)
 	}
 	for number, ethertype := range eti.Numbers {
-		i.Numbers[number] = ethertype
+		setPrimary(i.Numbers, number, ethertype, firstWins)
+	}
+	i.entries = append(i.entries, eti.entries...)
+}
+
+// init initializes the index maps, if not already done.
+func (i *EtherTypeIndex) init() {
+	if i.Names == nil {
+		i.Names = map[string]*EtherType{}
+	}
+	if i.Numbers == nil {
+		i.Numbers = map[uint16]*EtherType{}
 	}
 }
 
+// ByName returns the EtherType for the specified (alias) name, or nil if not
+// found.
+func (i *EtherTypeIndex) ByName(name string) *EtherType {
+	return i.Names[name]
+}
+
+// ByNumber returns the EtherType for the specified EtherType number, or nil if
+// not found.
+func (i *EtherTypeIndex) ByNumber(number uint16) *EtherType {
+	return i.Numbers[number]
+}
+
+// AllByName returns all EtherTypes with the specified (alias) name in the
+// order of their original definitions, including EtherTypes overridden by
+// later merges.
+func (i *EtherTypeIndex) AllByName(name string) []*EtherType {
+	var ethertypes []*EtherType
+	for ethertype := range i.All() {
+		if ethertype.Name == name || slices.Contains(ethertype.Aliases, name) {
+			ethertypes = append(ethertypes, ethertype)
+		}
+	}
+	return ethertypes
+}
+
+// AllByNumber returns all EtherTypes with the specified EtherType number in the
+// order of their original definitions, including EtherTypes overridden by
+// later merges.
+func (i *EtherTypeIndex) AllByNumber(number uint16) []*EtherType {
+	var ethertypes []*EtherType
+	for ethertype := range i.All() {
+		if ethertype.Number == number {
+			ethertypes = append(ethertypes, ethertype)
+		}
+	}
+	return ethertypes
+}
+
+// All returns an iterator over all EtherTypes merged into this index, in the
+// order of their original definitions. Each EtherType is yielded only once,
+// regardless of its aliases, and including EtherTypes that have been
+// overridden by later merges.
+func (i *EtherTypeIndex) All() iter.Seq[*EtherType] {
+	return allOf(i.entries)
+}
+
 // ParseEtherTypes parses EtherType definitions from the given Reader and
-// returns them as a list of EtherType objects.
+// returns them as a list of EtherType objects. Incomplete definitions are
+// silently skipped, while invalid EtherType numbers result in an error.
 func ParseEtherTypes(r io.Reader) ([]EtherType, error) {
+	return ParseEtherTypesWithOptions(r, ParseOptions{})
+}
+
+// ParseEtherTypesWithOptions parses EtherType definitions from the given
+// Reader as specified by the options and returns them as a list of EtherType
+// objects.
+func ParseEtherTypesWithOptions(r io.Reader, opts ParseOptions) ([]EtherType, error) {
 	ethertypes := []EtherType{}
+	lp := lineParser{opts: opts}
 
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
+		lp.next()
 		line := strings.TrimSpace(scanner.Text())
-		if strings.HasPrefix(line, "#") {
-			// Skip lines containing only comments
+		if line == "" || strings.HasPrefix(line, "#") {
+			// Skip empty lines and lines containing only comments
 			continue
 		}
-		components := strings.SplitN(line, "#", 2)
-		comment := ""
-		if len(components) > 1 {
-			comment = strings.TrimSpace(components[1])
-		}
-		fields := strings.Fields(components[0])
+		definition, comment := splitComment(line)
+		fields := strings.Fields(definition)
 		if len(fields) < 2 {
-			// Skip malformed entries
+			if err := lp.malformed(line, "missing EtherType number", false); err != nil {
+				return nil, err
+			}
 			continue
 		}
 		number, err := strconv.ParseUint(fields[1], 16, 16)
 		if err != nil {
-			return nil, err
+			if err := lp.malformed(line, "invalid EtherType number", true); err != nil {
+				return nil, err
+			}
+			continue
 		}
 		ethertypes = append(ethertypes, EtherType{
 			Name:    fields[0],
@@ -129,28 +225,62 @@ func ParseEtherTypes(r io.Reader) ([]EtherType, error) {
 		return nil, err
 	}
 
-	return ethertypes, nil
+	return ethertypes, lp.err()
 }
 
 // EtherTypeByName returns the EtherType details for the specified (native or
 // aliased) name, or nil if not defined.
 func EtherTypeByName(name string) *EtherType {
-	if EtherTypes.Numbers == nil {
-		EtherTypes = NewEtherTypeIndex(BuiltinEtherTypes)
-	}
-	return EtherTypes.Names[name]
+	idx := defaultEtherTypes.rlock()
+	defer defaultEtherTypes.runlock()
+	return idx.ByName(name)
 }
 
 // EtherTypeByNumber returns the EtherType details for the specified EtherType
 // number, or nil if not defined.
 func EtherTypeByNumber(number uint16) *EtherType {
-	if EtherTypes.Numbers == nil {
-		EtherTypes = NewEtherTypeIndex(BuiltinEtherTypes)
-	}
-	return EtherTypes.Numbers[number]
+	idx := defaultEtherTypes.rlock()
+	defer defaultEtherTypes.runlock()
+	return idx.ByNumber(number)
+}
+
+// AllEtherTypesByName returns all EtherType details for the specified (alias)
+// name, in the order of their definitions.
+func AllEtherTypesByName(name string) []*EtherType {
+	idx := defaultEtherTypes.rlock()
+	defer defaultEtherTypes.runlock()
+	return idx.AllByName(name)
+}
+
+// AllEtherTypesByNumber returns all EtherType details for the specified
+// EtherType number, in the order of their definitions.
+func AllEtherTypesByNumber(number uint16) []*EtherType {
+	idx := defaultEtherTypes.rlock()
+	defer defaultEtherTypes.runlock()
+	return idx.AllByNumber(number)
+}
+
+// AllEtherTypes returns an iterator over all EtherTypes in the EtherTypes index,
+// in the order of their original definitions.
+func AllEtherTypes() iter.Seq[*EtherType] {
+	idx := defaultEtherTypes.rlock()
+	defer defaultEtherTypes.runlock()
+	return idx.All()
+}
+
+// SetEtherTypes atomically replaces the EtherTypes index with the specified
+// index. It is safe to call SetEtherTypes while lookups are in flight in other
+// goroutines; these lookups finish on the old index. Please note that
+// EtherType objects returned from the old index stay valid.
+func SetEtherTypes(i EtherTypeIndex) {
+	defaultEtherTypes.set(i)
 }
 
 // EtherTypes is the index of EtherType names and numbers. If left to the zero
 // value, then it will be automatically initialized with the builtin
-// definitions upon first use of EtherTypeByName or EtherTypeByNumber.
+// definitions upon first use of EtherTypeByName, EtherTypeByNumber, et cetera.
+// This initialization is goroutine-safe.
+//
+// Directly assigning to EtherTypes or merging into it is not safe while
+// lookups are in flight; use SetEtherTypes instead.
 var EtherTypes EtherTypeIndex
@@ -16,6 +16,7 @@ package netdb
 
 import (
 	"os"
+	"slices"
 	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -111,6 +112,17 @@ RoMON	88BF	mikrotik-rommon mt-rommon		# MikroTik RoMON (unofficial)
 			Expect(idx.Numbers).To(HaveLen(1))
 			Expect(idx.Numbers).To(HaveKey(uint16(0x88BF)))
 		})
+		It("iterates over all EtherTypes in definition order", func() {
+			p, err := ParseEtherTypes(strings.NewReader(`
+RoMON	88BF	mikrotik-rommon mt-rommon		# MikroTik RoMON (unofficial)
+foobar	66
+barfoo	66
+`))
+			Expect(err).NotTo(HaveOccurred())
+			idx := NewEtherTypeIndex(p)
+			Expect(slices.Collect(idx.All())).To(HaveExactElements(
+				&p[0], &p[1], &p[2]))
+		})
 		It("merges indices", func() {
 			p, err := ParseEtherTypes(strings.NewReader(`
 RoMON	88BF	mikrotik-rommon mt-rommon		# MikroTik RoMON (unofficial)
@@ -8,12 +8,17 @@ import (
 
 // Where required, merges protocol and service descriptions from /etc/protocols
 // and /etc/services with the built-in database, replacing builtin descriptions
-// with those found in the files.
+// with those found in the files. The merged indices then get published
+// atomically, so this is safe even with lookups in flight.
 func Example_mergeEtc() {
+	protocols := netdb.NewProtocolIndex(netdb.BuiltinProtocols)
 	etcprotocols, _ := netdb.LoadProtocols("/etc/protocols")
-	netdb.Protocols.MergeIndex(etcprotocols)
-	etcservices, _ := netdb.LoadServices("/etc/services", netdb.Protocols)
-	netdb.Services.MergeIndex(etcservices)
+	protocols.MergeIndex(etcprotocols)
+	services := netdb.NewServiceIndex(netdb.BuiltinServices)
+	etcservices, _ := netdb.LoadServices("/etc/services", protocols)
+	services.MergeIndex(etcservices)
+	netdb.SetProtocols(protocols)
+	netdb.SetServices(services)
 	dns := netdb.ServiceByName("domain", "udp")
 	fmt.Printf("%s: %d via %s", dns.Name, dns.Port, dns.Protocol.Name)
 	// Output: domain: 53 via udp
@@ -23,9 +28,9 @@ func Example_mergeEtc() {
 // /etc/protocols and /etc/services, ignoring the builtin data completely.
 func Example_onlyEtc() {
 	etcprotocols, _ := netdb.LoadProtocols("/etc/protocols")
-	netdb.Protocols = etcprotocols
-	etcservices, _ := netdb.LoadServices("/etc/services", netdb.Protocols)
-	netdb.Services = etcservices
+	netdb.SetProtocols(etcprotocols)
+	etcservices, _ := netdb.LoadServices("/etc/services", etcprotocols)
+	netdb.SetServices(etcservices)
 	dns := netdb.ServiceByName("domain", "udp")
 	fmt.Printf("%s: %d via %s", dns.Name, dns.Port, dns.Protocol.Name)
 	// Output: domain: 53 via udp
@@ -47,3 +47,64 @@ func Example_etherTypeByNumber() {
 	fmt.Println(ipv4.Name)
 	// Output: IPv4
 }
+
+// Looks up the services within a range of port numbers for a specific
+// protocol.
+func Example_serviceByPortRange() {
+	for _, service := range netdb.ServiceByPortRange(20, 23, "tcp") {
+		fmt.Println(service.Port, service.Name)
+	}
+	// Output:
+	// 20 ftp-data
+	// 21 ftp
+	// 22 ssh
+	// 23 telnet
+}
+
+// Lists all EtherTypes in the order of their definition, with each EtherType
+// listed only once regardless of its aliases.
+func Example_allEtherTypes() {
+	for ethertype := range netdb.AllEtherTypes() {
+		if ethertype.Number == 0x0800 || ethertype.Number == 0x86dd {
+			fmt.Printf("%04x %s %v\n", ethertype.Number, ethertype.Name, ethertype.Aliases)
+		}
+	}
+	// Output:
+	// 0800 IPv4 [ip ip4]
+	// 86dd IPv6 [ip6]
+}
+
+// Looks up a service by its name case-insensitively, preferring TCP over SCTP
+// over UDP.
+func Example_queryServiceByName() {
+	https := netdb.QueryServiceByName("HTTPS", netdb.Query{
+		IgnoreCase: true,
+		Protocols:  []string{"tcp", "sctp", "udp"},
+	})
+	fmt.Printf("%s: %d/%s", https.Name, https.Port, https.ProtocolName)
+	// Output: https: 443/tcp
+}
+
+// Looks up services by consulting the sources configured for the "services"
+// database in an nsswitch.conf(5) file, where "files" refers to a services
+// file and any other sources fall back to the builtin database.
+func Example_nsswitch() {
+	nss, _ := netdb.LoadNSSwitch("test/nsswitch.conf")
+	files, _ := netdb.LoadServicesWithOptions("test/services",
+		netdb.NewProtocolIndex(netdb.BuiltinProtocols),
+		netdb.ParseOptions{KeepUnknownProtocols: true})
+	builtin := netdb.NewServiceIndex(netdb.BuiltinServices)
+	services := netdb.ServiceChain{
+		{Name: "nsswitch", Source: nss.ServiceChain(map[string]netdb.ServiceSource{
+			"files": &files,
+		})},
+		{Name: "builtin", Source: &builtin},
+	}
+	for _, name := range []string{"crash", "domain"} {
+		service := services.ByName(name, "")
+		fmt.Printf("%s: %d/%s\n", service.Name, service.Port, service.ProtocolName)
+	}
+	// Output:
+	// crash: 666/foobar
+	// domain: 53/tcp
+}
@@ -1,6 +1,6 @@
 module github.com/thediveo/netdb
 
-go 1.21
+go 1.23
 
 require (
 	github.com/onsi/ginkgo/v2 v2.17.1
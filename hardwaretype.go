@@ -0,0 +1,189 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"iter"
+	"slices"
+)
+
+// HardwareType describes a link-layer hardware type, as used in the ARP
+// hardware type field as well as for the link type of Linux network
+// interfaces (ARPHRD_*). Each HardwareType entry contains a name and the
+// two-octet (uint16) number. It also optionally contains name aliases as well
+// as a comment.
+//
+// The builtin hardware types are named after iproute2's "link/..." names, such
+// as "ether", with the Linux ARPHRD_* constant names as aliases.
+type HardwareType struct {
+	Name    string   // Official hardware type name, such as "ether".
+	Number  uint16   // Hardware type number value.
+	Aliases []string // List of aliases, such as "ARPHRD_ETHER".
+	Comment string   // Entry comment, if present.
+}
+
+// HardwareTypeIndex indexes the known hardware types by either name (native
+// as well as aliases) and by number.
+//
+// When multiple hardware types share the same name or number, the Precedence
+// rule decides which hardware type becomes the primary one. The Precedence
+// rule needs to be set before merging any hardware types.
+type HardwareTypeIndex struct {
+	Names      map[string]*HardwareType
+	Numbers    map[uint16]*HardwareType
+	Precedence Precedence // Rule for picking the primary hardware type.
+
+	entries []*HardwareType // all merged hardware types in definition order.
+}
+
+// NewHardwareTypeIndex returns a HardwareTypeIndex object initialized with the
+// specified hardware types.
+func NewHardwareTypeIndex(hwtypes []HardwareType) HardwareTypeIndex {
+	i := HardwareTypeIndex{
+		Names:   map[string]*HardwareType{},
+		Numbers: map[uint16]*HardwareType{},
+	}
+	i.Merge(hwtypes)
+	return i
+}
+
+// Merge a list of HardwareType descriptions into the current hardware types
+// index, potentially overriding existing entries in the index in case of
+// duplicates, depending on the index's Precedence rule.
+func (i *HardwareTypeIndex) Merge(hwtypes []HardwareType) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for idx, hwtype := range hwtypes {
+		setPrimary(i.Names, hwtype.Name, &hwtypes[idx], firstWins)
+		for _, alias := range hwtype.Aliases {
+			setPrimary(i.Names, alias, &hwtypes[idx], firstWins)
+		}
+		setPrimary(i.Numbers, hwtype.Number, &hwtypes[idx], firstWins)
+		i.entries = append(i.entries, &hwtypes[idx])
+	}
+}
+
+// MergeIndex merges another HardwareTypeIndex into the current index,
+// potentially overriding existing entries in case of duplicates, depending on
+// the index's Precedence rule.
+func (i *HardwareTypeIndex) MergeIndex(hti HardwareTypeIndex) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for name, hwtype := range hti.Names {
+		setPrimary(i.Names, name, hwtype, firstWins)
+	}
+	for number, hwtype := range hti.Numbers {
+		setPrimary(i.Numbers, number, hwtype, firstWins)
+	}
+	i.entries = append(i.entries, hti.entries...)
+}
+
+// init initializes the index maps, if not already done.
+func (i *HardwareTypeIndex) init() {
+	if i.Names == nil {
+		i.Names = map[string]*HardwareType{}
+	}
+	if i.Numbers == nil {
+		i.Numbers = map[uint16]*HardwareType{}
+	}
+}
+
+// ByName returns the HardwareType for the specified (alias) name, or nil if
+// not found.
+func (i *HardwareTypeIndex) ByName(name string) *HardwareType {
+	return i.Names[name]
+}
+
+// ByNumber returns the HardwareType for the specified hardware type number, or
+// nil if not found.
+func (i *HardwareTypeIndex) ByNumber(number uint16) *HardwareType {
+	return i.Numbers[number]
+}
+
+// AllByName returns all hardware types with the specified (alias) name in the
+// order of their original definitions, including hardware types overridden by
+// later merges.
+func (i *HardwareTypeIndex) AllByName(name string) []*HardwareType {
+	var hwtypes []*HardwareType
+	for hwtype := range i.All() {
+		if hwtype.Name == name || slices.Contains(hwtype.Aliases, name) {
+			hwtypes = append(hwtypes, hwtype)
+		}
+	}
+	return hwtypes
+}
+
+// AllByNumber returns all hardware types with the specified number in the
+// order of their original definitions, including hardware types overridden by
+// later merges.
+func (i *HardwareTypeIndex) AllByNumber(number uint16) []*HardwareType {
+	var hwtypes []*HardwareType
+	for hwtype := range i.All() {
+		if hwtype.Number == number {
+			hwtypes = append(hwtypes, hwtype)
+		}
+	}
+	return hwtypes
+}
+
+// All returns an iterator over all hardware types merged into this index, in
+// the order of their original definitions. Each hardware type is yielded only
+// once, regardless of its aliases, and including hardware types that have been
+// overridden by later merges.
+func (i *HardwareTypeIndex) All() iter.Seq[*HardwareType] {
+	return allOf(i.entries)
+}
+
+// HardwareTypeByName returns the HardwareType details for the specified
+// (native or aliased) name, or nil if not defined.
+func HardwareTypeByName(name string) *HardwareType {
+	idx := defaultHardwareTypes.rlock()
+	defer defaultHardwareTypes.runlock()
+	return idx.ByName(name)
+}
+
+// HardwareTypeByNumber returns the HardwareType details for the specified
+// hardware type number, such as a Linux network interface's ARPHRD link type,
+// or nil if not defined.
+func HardwareTypeByNumber(number uint16) *HardwareType {
+	idx := defaultHardwareTypes.rlock()
+	defer defaultHardwareTypes.runlock()
+	return idx.ByNumber(number)
+}
+
+// AllHardwareTypes returns an iterator over all hardware types in the hardware
+// types index, in the order of their original definitions.
+func AllHardwareTypes() iter.Seq[*HardwareType] {
+	idx := defaultHardwareTypes.rlock()
+	defer defaultHardwareTypes.runlock()
+	return idx.All()
+}
+
+// SetHardwareTypes atomically replaces the hardware types index with the
+// specified index. It is safe to call SetHardwareTypes while lookups are in
+// flight in other goroutines; these lookups finish on the old index. Please
+// note that HardwareType objects returned from the old index stay valid.
+func SetHardwareTypes(i HardwareTypeIndex) {
+	defaultHardwareTypes.set(i)
+}
+
+// HardwareTypes is the index of hardware type names and numbers. If left to
+// the zero value, then it will be automatically initialized with the builtin
+// definitions upon first use of HardwareTypeByName, HardwareTypeByNumber, et
+// cetera. This initialization is goroutine-safe.
+//
+// Directly assigning to HardwareTypes or merging into it is not safe while
+// lookups are in flight; use SetHardwareTypes instead.
+var HardwareTypes HardwareTypeIndex
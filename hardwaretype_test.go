@@ -0,0 +1,69 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("hardware types", func() {
+
+	It("has unique builtin numbers and names", func() {
+		idx := NewHardwareTypeIndex(BuiltinHardwareTypes)
+		Expect(idx.Numbers).To(HaveLen(len(BuiltinHardwareTypes)))
+		for hwtype := range idx.All() {
+			Expect(idx.AllByName(hwtype.Name)).To(HaveLen(1), "duplicate name %q", hwtype.Name)
+		}
+	})
+
+	It("looks up builtin hardware types", func() {
+		Expect(HardwareTypeByNumber(1)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Name":    Equal("ether"),
+			"Aliases": ConsistOf("ARPHRD_ETHER"),
+		})))
+		Expect(HardwareTypeByNumber(772).Name).To(Equal("loopback"))
+		Expect(HardwareTypeByNumber(776).Name).To(Equal("sit"))
+		Expect(HardwareTypeByNumber(0xffff).Name).To(Equal("void"))
+		Expect(HardwareTypeByNumber(1000)).To(BeNil())
+		Expect(HardwareTypeByName("ARPHRD_IEEE80211").Number).To(Equal(uint16(801)))
+		Expect(HardwareTypeByName("pure-ip").Number).To(Equal(uint16(35)))
+		Expect(HardwareTypeByName("foobar")).To(BeNil())
+		Expect(slices.Collect(AllHardwareTypes())).To(HaveLen(len(BuiltinHardwareTypes)))
+	})
+
+	It("merges and replaces", func() {
+		HardwareTypes = HardwareTypeIndex{}
+		DeferCleanup(func() { HardwareTypes = HardwareTypeIndex{} })
+
+		idx := NewHardwareTypeIndex(BuiltinHardwareTypes)
+		idx.MergeIndex(NewHardwareTypeIndex([]HardwareType{
+			{Name: "foo", Number: 1},
+		}))
+		Expect(idx.ByNumber(1).Name).To(Equal("foo"))
+		Expect(idx.ByName("ether").Number).To(Equal(uint16(1)))
+		Expect(idx.AllByNumber(1)).To(HaveExactElements(
+			HaveField("Name", "ether"),
+			HaveField("Name", "foo"),
+		))
+
+		SetHardwareTypes(idx)
+		Expect(HardwareTypeByNumber(1).Name).To(Equal("foo"))
+	})
+
+})
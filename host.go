@@ -0,0 +1,256 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"net/netip"
+	"os"
+	"strings"
+)
+
+// Host describes a single hosts(5) entry, consisting of an IPv4 or IPv6
+// address, the canonical host name, optional alias names, and an optional
+// comment. IPv6 addresses may carry a zone, such as "fe80::1%eth0".
+//
+// On purpose, we don't stick with the stuttering POSIX C library names, but
+// instead aim for more Go-like type names. After all, Go isn't similar to C,
+// except for using letters, signs, and braces.
+type Host struct {
+	Addr    netip.Addr // IP address, including an optional zone.
+	Name    string     // Canonical host name.
+	Aliases []string   // List of aliases.
+	Comment string     // Entry comment, if present.
+}
+
+// HostIndex indexes the known hosts by either name (canonical as well as
+// aliases) and by address. Names are indexed in lower case, as host name
+// lookups are case-insensitive.
+//
+// Following glibc, with the DefaultPrecedence rule the first entry for a name
+// or address wins, both when merging host lists as well as other indexes. As
+// in glibc, AddrsByName returns the addresses of all entries for a name.
+type HostIndex struct {
+	Names      map[string]*Host     // Index by lower-case host name, including aliases.
+	Addrs      map[netip.Addr]*Host // Index by address.
+	Precedence Precedence           // Rule for picking the primary host.
+
+	entries []*Host // all merged hosts in definition order.
+}
+
+// NewHostIndex returns a HostIndex object initialized with the specified
+// hosts.
+func NewHostIndex(hosts []Host) HostIndex {
+	i := HostIndex{
+		Names: map[string]*Host{},
+		Addrs: map[netip.Addr]*Host{},
+	}
+	i.Merge(hosts)
+	return i
+}
+
+// LoadHosts returns a HostIndex object initialized from the definitions in
+// the named file, such as "/etc/hosts".
+func LoadHosts(name string) (HostIndex, error) {
+	return LoadHostsWithOptions(name, ParseOptions{})
+}
+
+// LoadHostsWithOptions returns a HostIndex object initialized from the
+// definitions in the named file, parsing it as specified by the options. If
+// the options don't specify a file name, then the specified name is used. In
+// ParseCollectAll mode, the returned index contains all well-formed
+// definitions, even when a ParseErrors error is returned.
+func LoadHostsWithOptions(name string, opts ParseOptions) (HostIndex, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return NewHostIndex(nil), err
+	}
+	defer f.Close()
+	if opts.Filename == "" {
+		opts.Filename = name
+	}
+	hosts, err := ParseHostsWithOptions(f, opts)
+	if hosts == nil {
+		return NewHostIndex(nil), err
+	}
+	return NewHostIndex(hosts), err
+}
+
+// Merge a list of Host descriptions into the current hosts index. In case of
+// duplicates, the index's Precedence rule decides whether existing entries
+// get overridden.
+func (i *HostIndex) Merge(hosts []Host) {
+	i.init()
+	firstWins := i.Precedence.firstWins(true)
+	for idx, host := range hosts {
+		setPrimary(i.Names, strings.ToLower(host.Name), &hosts[idx], firstWins)
+		for _, alias := range host.Aliases {
+			setPrimary(i.Names, strings.ToLower(alias), &hosts[idx], firstWins)
+		}
+		setPrimary(i.Addrs, host.Addr, &hosts[idx], firstWins)
+		i.entries = append(i.entries, &hosts[idx])
+	}
+}
+
+// MergeIndex merges another HostIndex into the current index. In case of
+// duplicates, the index's Precedence rule decides whether existing entries
+// get overridden.
+func (i *HostIndex) MergeIndex(hi HostIndex) {
+	i.init()
+	firstWins := i.Precedence.firstWins(true)
+	for name, host := range hi.Names {
+		setPrimary(i.Names, name, host, firstWins)
+	}
+	for addr, host := range hi.Addrs {
+		setPrimary(i.Addrs, addr, host, firstWins)
+	}
+	i.entries = append(i.entries, hi.entries...)
+}
+
+// init initializes the index maps, if not already done.
+func (i *HostIndex) init() {
+	if i.Names == nil {
+		i.Names = map[string]*Host{}
+	}
+	if i.Addrs == nil {
+		i.Addrs = map[netip.Addr]*Host{}
+	}
+}
+
+// ByName returns the Host for the specified (alias) name, or nil if not found.
+// The name is matched case-insensitively.
+func (i *HostIndex) ByName(name string) *Host {
+	return i.Names[strings.ToLower(name)]
+}
+
+// ByAddr returns the Host for the specified address, or nil if not found. If
+// there is no entry for an address with a zone, then ByAddr falls back to an
+// entry for the same address without zone. IPv4-mapped IPv6 addresses also
+// match IPv4 entries.
+func (i *HostIndex) ByAddr(addr netip.Addr) *Host {
+	if host, ok := i.Addrs[addr]; ok {
+		return host
+	}
+	if addr.Zone() != "" {
+		if host, ok := i.Addrs[addr.WithZone("")]; ok {
+			return host
+		}
+	}
+	if addr.Is4In6() {
+		return i.Addrs[addr.Unmap()]
+	}
+	return nil
+}
+
+// AllByName returns all hosts with the specified (alias) name in the order of
+// their original definitions. The name is matched case-insensitively.
+func (i *HostIndex) AllByName(name string) []*Host {
+	var hosts []*Host
+	for host := range i.All() {
+		if host.hasName(name) {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// AddrsByName returns the addresses of all hosts with the specified (alias)
+// name in the order of their original definitions, without duplicates. The
+// name is matched case-insensitively.
+func (i *HostIndex) AddrsByName(name string) []netip.Addr {
+	var addrs []netip.Addr
+	seen := map[netip.Addr]struct{}{}
+	for _, host := range i.AllByName(name) {
+		if _, ok := seen[host.Addr]; ok {
+			continue
+		}
+		seen[host.Addr] = struct{}{}
+		addrs = append(addrs, host.Addr)
+	}
+	return addrs
+}
+
+// All returns an iterator over all hosts merged into this index, in the order
+// of their original definitions. Each host is yielded only once, regardless of
+// its aliases, and including hosts that have been shadowed by earlier
+// definitions.
+func (i *HostIndex) All() iter.Seq[*Host] {
+	return allOf(i.entries)
+}
+
+// hasName returns true if the host has the specified canonical or alias name,
+// ignoring case.
+func (h *Host) hasName(name string) bool {
+	if strings.EqualFold(h.Name, name) {
+		return true
+	}
+	for _, alias := range h.Aliases {
+		if strings.EqualFold(alias, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseHosts parses host definitions from the given Reader and returns them as
+// a list of Host(s). Similar to glibc, incomplete definitions as well as
+// definitions with invalid addresses are silently skipped.
+func ParseHosts(r io.Reader) ([]Host, error) {
+	return ParseHostsWithOptions(r, ParseOptions{})
+}
+
+// ParseHostsWithOptions parses host definitions from the given Reader as
+// specified by the options and returns them as a list of Host(s).
+func ParseHostsWithOptions(r io.Reader, opts ParseOptions) ([]Host, error) {
+	hosts := []Host{}
+	lp := lineParser{opts: opts}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lp.next()
+		line := strings.TrimSpace(scanner.Text())
+		definition, comment := splitComment(line)
+		fields := strings.Fields(definition)
+		if len(fields) == 0 {
+			continue // skip empty lines and comment lines.
+		}
+		if len(fields) < 2 {
+			if err := lp.malformed(line, "missing host name", false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		addr, err := netip.ParseAddr(fields[0])
+		if err != nil {
+			if err := lp.malformed(line, "invalid address", false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		hosts = append(hosts, Host{
+			Addr:    addr,
+			Name:    fields[1],
+			Aliases: fields[2:],
+			Comment: comment,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return hosts, lp.err()
+}
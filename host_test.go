@@ -0,0 +1,143 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"net/netip"
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("hosts", func() {
+
+	Context("parsing descriptions", func() {
+
+		It("returns correct descriptions", func() {
+			h, err := ParseHosts(strings.NewReader(`
+# A comment
+127.0.0.1	localhost
+::1		localhost ip6-localhost	# IPv6 loopback
+fe80::1%eth0	router
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(h).To(HaveExactElements(
+				MatchFields(IgnoreExtras, Fields{
+					"Addr":    Equal(netip.MustParseAddr("127.0.0.1")),
+					"Name":    Equal("localhost"),
+					"Aliases": BeEmpty(),
+					"Comment": BeEmpty(),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Addr":    Equal(netip.MustParseAddr("::1")),
+					"Name":    Equal("localhost"),
+					"Aliases": ConsistOf("ip6-localhost"),
+					"Comment": Equal("IPv6 loopback"),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Addr": Equal(netip.MustParseAddr("fe80::1%eth0")),
+					"Name": Equal("router"),
+				}),
+			))
+		})
+
+		It("silently skips malformed definitions", func() {
+			h, err := ParseHosts(strings.NewReader(`
+127.0.0.1
+foo bar
+300.1.2.3 baz
+127.0.0.1 localhost
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(h).To(HaveExactElements(HaveField("Name", "localhost")))
+		})
+
+		It("collects all errors", func() {
+			_, err := ParseHostsWithOptions(strings.NewReader("127.0.0.1\nfoo bar\n"),
+				ParseOptions{Mode: ParseCollectAll})
+			Expect(err).To(HaveLen(2))
+			Expect(err.(ParseErrors)[0].Reason).To(Equal("missing host name"))
+			Expect(err.(ParseErrors)[1].Reason).To(Equal("invalid address"))
+		})
+
+	})
+
+	Context("index", func() {
+
+		var idx HostIndex
+
+		BeforeEach(func() {
+			var err error
+			idx, err = LoadHosts("test/hosts")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reports an error for a non-existing file", func() {
+			_, err := LoadHosts("test/non-existing-hosts")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("looks up names case-insensitively, first entry winning", func() {
+			Expect(slices.Collect(idx.All())).To(HaveLen(6))
+			Expect(idx.ByName("LOCALHOST").Addr).To(Equal(netip.MustParseAddr("127.0.0.1")))
+			Expect(idx.ByName("myhost").Addr).To(Equal(netip.MustParseAddr("127.0.1.1")))
+			Expect(idx.ByName("ip6-loopback").Addr).To(Equal(netip.MustParseAddr("::1")))
+			Expect(idx.ByName("foo")).To(BeNil())
+		})
+
+		It("returns all addresses of a name", func() {
+			Expect(idx.AddrsByName("localhost")).To(HaveExactElements(
+				netip.MustParseAddr("127.0.0.1"),
+				netip.MustParseAddr("::1"),
+			))
+			Expect(idx.AllByName("MYHOST")).To(HaveExactElements(
+				HaveField("Name", "myhost.example.org"),
+				HaveField("Name", "MyHost"),
+			))
+			Expect(idx.AddrsByName("foo")).To(BeEmpty())
+		})
+
+		It("looks up addresses, first entry winning", func() {
+			Expect(idx.ByAddr(netip.MustParseAddr("192.0.2.1")).Name).To(Equal("MyHost"))
+			Expect(idx.ByAddr(netip.MustParseAddr("::ffff:127.0.0.1")).Name).To(Equal("localhost"))
+			Expect(idx.ByAddr(netip.MustParseAddr("fe80::1%eth0")).Name).To(Equal("router-ll"))
+			Expect(idx.ByAddr(netip.MustParseAddr("fe80::1"))).To(BeNil())
+			Expect(idx.ByAddr(netip.MustParseAddr("::1%lo")).Name).To(Equal("localhost"))
+			Expect(idx.ByAddr(netip.MustParseAddr("fe80::1%eth1"))).To(BeNil())
+			Expect(idx.ByAddr(netip.MustParseAddr("192.0.2.2"))).To(BeNil())
+		})
+
+		It("merges", func() {
+			other := NewHostIndex([]Host{
+				{Addr: netip.MustParseAddr("10.0.0.1"), Name: "localhost"},
+				{Addr: netip.MustParseAddr("10.0.0.2"), Name: "new"},
+			})
+			idx.MergeIndex(other)
+			Expect(idx.ByName("localhost").Addr).To(Equal(netip.MustParseAddr("127.0.0.1")))
+			Expect(idx.ByName("new").Addr).To(Equal(netip.MustParseAddr("10.0.0.2")))
+			Expect(idx.AddrsByName("localhost")).To(HaveLen(3))
+
+			last := HostIndex{Precedence: LastWins}
+			last.MergeIndex(idx)
+			last.MergeIndex(other)
+			Expect(last.ByName("localhost").Addr).To(Equal(netip.MustParseAddr("10.0.0.1")))
+		})
+
+	})
+
+})
@@ -0,0 +1,214 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// IANAOptions controls which entries of the IANA Service Name and Transport
+// Protocol Port Number Registry are returned.
+type IANAOptions struct {
+	IncludeReserved   bool // include reserved ports, without service names.
+	IncludeUnassigned bool // include unassigned ports, without service names.
+}
+
+// ianaRecord is a single registry record, independent of its CSV or XML
+// representation.
+type ianaRecord struct {
+	name        string
+	port        string
+	protocol    string
+	description string
+	assignee    string
+	contact     string
+	reference   string
+}
+
+// ParseIANAServicesCSV parses the CSV form of the IANA Service Name and
+// Transport Protocol Port Number Registry from the given Reader and returns
+// its entries as a list of Service(s). The registry is available from
+// https://www.iana.org/assignments/service-names-port-numbers/service-names-port-numbers.csv.
+//
+// Registry entries without port numbers are skipped. Port ranges are kept as
+// port range Service(s). Entries with protocols not in the
+// specified ProtocolIndex are kept, with their Service.Protocol set to nil.
+func ParseIANAServicesCSV(r io.Reader, p ProtocolIndex, opts IANAOptions) ([]Service, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for idx, column := range header {
+		columns[strings.TrimSpace(column)] = idx
+	}
+	for _, column := range []string{"Service Name", "Port Number", "Transport Protocol"} {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("IANA registry CSV lacks %q column", column)
+		}
+	}
+	field := func(row []string, column string) string {
+		if idx, ok := columns[column]; ok && idx < len(row) {
+			return strings.TrimSpace(row[idx])
+		}
+		return ""
+	}
+
+	services := []Service{}
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		services, err = appendIANARecord(services, ianaRecord{
+			name:        field(row, "Service Name"),
+			port:        field(row, "Port Number"),
+			protocol:    field(row, "Transport Protocol"),
+			description: field(row, "Description"),
+			assignee:    field(row, "Assignee"),
+			contact:     field(row, "Contact"),
+			reference:   field(row, "Reference"),
+		}, p, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return services, nil
+}
+
+// ianaXMLXref is a cross reference in the XML form of the IANA registry.
+type ianaXMLXref struct {
+	Type string `xml:"type,attr"`
+	Data string `xml:"data,attr"`
+}
+
+// ianaXMLRecord is a record in the XML form of the IANA registry.
+type ianaXMLRecord struct {
+	Name        string        `xml:"name"`
+	Protocol    string        `xml:"protocol"`
+	Number      string        `xml:"number"`
+	Description string        `xml:"description"`
+	Xrefs       []ianaXMLXref `xml:"xref"`
+	Assignees   []ianaXMLXref `xml:"assignee>xref"`
+	Contacts    []ianaXMLXref `xml:"contact>xref"`
+}
+
+// ParseIANAServicesXML parses the XML form of the IANA Service Name and
+// Transport Protocol Port Number Registry from the given Reader and returns
+// its entries as a list of Service(s). The registry is available from
+// https://www.iana.org/assignments/service-names-port-numbers/service-names-port-numbers.xml.
+//
+// Cross references are rendered in the same way as in the CSV form of the
+// registry, such as "[RFC959]". Otherwise, ParseIANAServicesXML behaves the
+// same as ParseIANAServicesCSV.
+func ParseIANAServicesXML(r io.Reader, p ProtocolIndex, opts IANAOptions) ([]Service, error) {
+	services := []Service{}
+	dec := xml.NewDecoder(r)
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+		var record ianaXMLRecord
+		if err := dec.DecodeElement(&record, &start); err != nil {
+			return nil, err
+		}
+		services, err = appendIANARecord(services, ianaRecord{
+			name:        strings.TrimSpace(record.Name),
+			port:        strings.TrimSpace(record.Number),
+			protocol:    strings.TrimSpace(record.Protocol),
+			description: strings.TrimSpace(record.Description),
+			assignee:    ianaXrefs(record.Assignees),
+			contact:     ianaXrefs(record.Contacts),
+			reference:   ianaXrefs(record.Xrefs),
+		}, p, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return services, nil
+}
+
+// ianaXrefs renders cross references the same way as the CSV form of the IANA
+// registry does, such as "[RFC959]" and "[Jon_Postel]".
+func ianaXrefs(xrefs []ianaXMLXref) string {
+	refs := make([]string, 0, len(xrefs))
+	for _, xref := range xrefs {
+		data := xref.Data
+		if xref.Type == "rfc" {
+			data = strings.ToUpper(data)
+		}
+		refs = append(refs, "["+data+"]")
+	}
+	return strings.Join(refs, "")
+}
+
+// appendIANARecord appends the Service(s) described by the specified registry
+// record to the list of services, returning the updated list.
+func appendIANARecord(services []Service, record ianaRecord, p ProtocolIndex, opts IANAOptions) ([]Service, error) {
+	if record.port == "" {
+		return services, nil // skip service names without port numbers.
+	}
+	assignment := PortAssigned
+	if record.name == "" {
+		switch strings.ToLower(record.description) {
+		case "reserved":
+			if !opts.IncludeReserved {
+				return services, nil
+			}
+			assignment = PortReserved
+		case "unassigned":
+			if !opts.IncludeUnassigned {
+				return services, nil
+			}
+			assignment = PortUnassigned
+		default:
+			return services, nil
+		}
+	}
+	first, last, err := parsePortRange(record.port)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q in IANA registry", err.Error(), record.port)
+	}
+	return append(services, Service{
+		Name:         record.name,
+		Port:         first,
+		LastPort:     last,
+		ProtocolName: record.protocol,
+		Protocol:     p.Names[record.protocol],
+		Aliases:      []string{},
+		Description:  record.description,
+		Assignee:     record.assignee,
+		Contact:      record.contact,
+		Reference:    record.reference,
+		Assignment:   assignment,
+	}), nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("IANA service registry", func() {
+
+	protos := NewProtocolIndex(BuiltinProtocols)
+
+	DescribeTable("parsing registry forms",
+		func(name string, parse func(io.Reader, ProtocolIndex, IANAOptions) ([]Service, error)) {
+			f, err := os.Open(name)
+			Expect(err).NotTo(HaveOccurred())
+			defer f.Close()
+			services, err := parse(f, protos, IANAOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(services).To(HaveExactElements(
+				MatchFields(IgnoreExtras, Fields{
+					"Name":         Equal("tcpmux"),
+					"Port":         Equal(1),
+					"ProtocolName": Equal("tcp"),
+					"Protocol":     PointTo(HaveField("Number", uint8(6))),
+					"Description":  Equal("TCP Port Service Multiplexer"),
+					"Assignee":     Equal("[Mark_Lottor]"),
+					"Contact":      Equal("[Mark_Lottor]"),
+					"Assignment":   Equal(PortAssigned),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Name":      Equal("ftp"),
+					"Port":      Equal(21),
+					"Reference": Equal("[RFC959]"),
+				}),
+				MatchFields(IgnoreExtras, Fields{"Name": Equal("x11"), "Port": Equal(6000), "LastPort": Equal(6002)}),
+				MatchFields(IgnoreExtras, Fields{
+					"Name":         Equal("sctp-tunneling"),
+					"ProtocolName": Equal("sctp"),
+					"Protocol":     PointTo(HaveField("Number", uint8(132))),
+				}),
+			))
+
+			_, err = f.Seek(0, io.SeekStart)
+			Expect(err).NotTo(HaveOccurred())
+			services, err = parse(f, protos, IANAOptions{IncludeReserved: true, IncludeUnassigned: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(services).To(ContainElements(
+				MatchFields(IgnoreExtras, Fields{"Port": Equal(0), "Assignment": Equal(PortReserved)}),
+				MatchFields(IgnoreExtras, Fields{"Port": Equal(4), "Assignment": Equal(PortUnassigned)}),
+			))
+		},
+		Entry("CSV", "test/iana-services.csv", ParseIANAServicesCSV),
+		Entry("XML", "test/iana-services.xml", ParseIANAServicesXML),
+	)
+
+	It("reports malformed CSV registries", func() {
+		_, err := ParseIANAServicesCSV(strings.NewReader(""), protos, IANAOptions{})
+		Expect(err).To(HaveOccurred())
+		_, err = ParseIANAServicesCSV(strings.NewReader("Service Name,Port Number\n"), protos, IANAOptions{})
+		Expect(err).To(MatchError(ContainSubstring(`lacks "Transport Protocol" column`)))
+		_, err = ParseIANAServicesCSV(strings.NewReader(`Service Name,Port Number,Transport Protocol
+foo,"bar
+`), protos, IANAOptions{})
+		Expect(err).To(HaveOccurred())
+		_, err = ParseIANAServicesCSV(strings.NewReader(`Service Name,Port Number,Transport Protocol
+foo,66x,tcp
+`), protos, IANAOptions{})
+		Expect(err).To(MatchError(ContainSubstring(`invalid port number "66x" in IANA registry`)))
+		_, err = ParseIANAServicesCSV(strings.NewReader(`Service Name,Port Number,Transport Protocol
+foo,66-6x,tcp
+`), protos, IANAOptions{})
+		Expect(err).To(MatchError(ContainSubstring(`invalid port range "66-6x" in IANA registry`)))
+	})
+
+	It("reports malformed XML registries", func() {
+		_, err := ParseIANAServicesXML(strings.NewReader("<registry><record>"), protos, IANAOptions{})
+		Expect(err).To(HaveOccurred())
+		_, err = ParseIANAServicesXML(strings.NewReader("<registry><record><name>foo</name><number>x</number></record></registry>"), protos, IANAOptions{})
+		Expect(err).To(HaveOccurred())
+		_, err = ParseIANAServicesXML(strings.NewReader("<registry"), protos, IANAOptions{})
+		Expect(err).To(HaveOccurred())
+	})
+
+})
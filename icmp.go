@@ -0,0 +1,274 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import "iter"
+
+// ICMPType describes an ICMP or ICMPv6 message type with its type number, a
+// short name (such as "echo-request"), its IANA registry description (such as
+// "Echo Request"), and the message codes defined for this type, if any.
+type ICMPType struct {
+	Type        uint8      // ICMP(v6) type number.
+	Name        string     // Short name, such as "destination-unreachable".
+	Description string     // IANA registry description.
+	Reference   string     // Reference(s), such as "[RFC792]".
+	Deprecated  bool       // Deprecated message type.
+	Codes       []ICMPCode // Message codes, if any.
+}
+
+// ICMPCode describes a message code of an ICMP or ICMPv6 message type with its
+// code number, a short name (such as "port-unreachable"), and its IANA
+// registry description.
+type ICMPCode struct {
+	Code        uint8  // ICMP(v6) code number.
+	Name        string // Short name, such as "port-unreachable".
+	Description string // IANA registry description.
+}
+
+// Code returns the ICMPCode for the specified code number of this message
+// type, or nil if not defined.
+func (t *ICMPType) Code(code uint8) *ICMPCode {
+	for idx := range t.Codes {
+		if t.Codes[idx].Code == code {
+			return &t.Codes[idx]
+		}
+	}
+	return nil
+}
+
+// ICMPIndex indexes the known message types of either ICMP or ICMPv6 by type
+// number and by name. It references the Protocol the message types belong to.
+//
+// When multiple message types share the same name or number, the Precedence
+// rule decides which message type becomes the primary one. The Precedence
+// rule needs to be set before merging any message types.
+type ICMPIndex struct {
+	Protocol   *Protocol            // Protocol, that is, "icmp" or "ipv6-icmp".
+	Names      map[string]*ICMPType // Index by message type name.
+	Numbers    map[uint8]*ICMPType  // Index by message type number.
+	Precedence Precedence           // Rule for picking the primary message type.
+
+	entries []*ICMPType // all merged message types in definition order.
+}
+
+// NewICMPIndex returns an ICMPIndex object for the specified Protocol,
+// initialized with the specified message types.
+func NewICMPIndex(proto *Protocol, types []ICMPType) ICMPIndex {
+	i := ICMPIndex{
+		Protocol: proto,
+		Names:    map[string]*ICMPType{},
+		Numbers:  map[uint8]*ICMPType{},
+	}
+	i.Merge(types)
+	return i
+}
+
+// Merge a list of ICMPType descriptions into the current index, potentially
+// overriding existing entries in the index in case of duplicates, depending
+// on the index's Precedence rule.
+func (i *ICMPIndex) Merge(types []ICMPType) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for idx, typ := range types {
+		setPrimary(i.Names, typ.Name, &types[idx], firstWins)
+		setPrimary(i.Numbers, typ.Type, &types[idx], firstWins)
+		i.entries = append(i.entries, &types[idx])
+	}
+}
+
+// MergeIndex merges another ICMPIndex into the current index, potentially
+// overriding existing entries in case of duplicates, depending on the index's
+// Precedence rule.
+func (i *ICMPIndex) MergeIndex(ii ICMPIndex) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for name, typ := range ii.Names {
+		setPrimary(i.Names, name, typ, firstWins)
+	}
+	for number, typ := range ii.Numbers {
+		setPrimary(i.Numbers, number, typ, firstWins)
+	}
+	i.entries = append(i.entries, ii.entries...)
+}
+
+// init initializes the index maps, if not already done.
+func (i *ICMPIndex) init() {
+	if i.Names == nil {
+		i.Names = map[string]*ICMPType{}
+	}
+	if i.Numbers == nil {
+		i.Numbers = map[uint8]*ICMPType{}
+	}
+}
+
+// ByNumber returns the ICMPType for the specified type number, or nil if not
+// found.
+func (i *ICMPIndex) ByNumber(typ uint8) *ICMPType {
+	return i.Numbers[typ]
+}
+
+// ByTypeCode returns the ICMPType and ICMPCode for the specified type and code
+// numbers. If the type is known, but not the code, then the returned ICMPCode
+// is nil. If the type is unknown, then both are nil.
+func (i *ICMPIndex) ByTypeCode(typ uint8, code uint8) (*ICMPType, *ICMPCode) {
+	t := i.Numbers[typ]
+	if t == nil {
+		return nil, nil
+	}
+	return t, t.Code(code)
+}
+
+// ByName returns the ICMPType for the specified message type name, or nil if
+// not found.
+func (i *ICMPIndex) ByName(name string) *ICMPType {
+	return i.Names[name]
+}
+
+// ByCodeName returns the ICMPType and ICMPCode for the specified message code
+// name, such as "port-unreachable". If multiple message codes share the same
+// name, then the first one in definition order wins. ByCodeName returns nil,
+// nil if the code name is unknown.
+func (i *ICMPIndex) ByCodeName(name string) (*ICMPType, *ICMPCode) {
+	for t := range i.All() {
+		for idx := range t.Codes {
+			if t.Codes[idx].Name == name {
+				return t, &t.Codes[idx]
+			}
+		}
+	}
+	return nil, nil
+}
+
+// All returns an iterator over all message types merged into this index, in
+// the order of their original definitions, including message types that have
+// been overridden by later merges.
+func (i *ICMPIndex) All() iter.Seq[*ICMPType] {
+	return allOf(i.entries)
+}
+
+// ICMPTypes returns the ICMP message types index for the "icmp" and
+// "ipv6-icmp" protocols, based on their protocol numbers 1 and 58; that is,
+// (a copy of) either ICMPv4Types or ICMPv6Types. For other protocols, as well
+// as for a nil Protocol, it returns nil.
+func (p *Protocol) ICMPTypes() *ICMPIndex {
+	if p == nil {
+		return nil
+	}
+	var l *lazyIndex[ICMPIndex]
+	switch p.Number {
+	case 1:
+		l = &defaultICMPv4Types
+	case 58:
+		l = &defaultICMPv6Types
+	default:
+		return nil
+	}
+	idx := *l.rlock()
+	defer l.runlock()
+	return &idx
+}
+
+// ICMPv4TypeByName returns the ICMP message type for the specified name, such
+// as "echo-request", or nil if not defined.
+func ICMPv4TypeByName(name string) *ICMPType {
+	idx := defaultICMPv4Types.rlock()
+	defer defaultICMPv4Types.runlock()
+	return idx.ByName(name)
+}
+
+// ICMPv4TypeByNumber returns the ICMP message type for the specified type
+// number, or nil if not defined.
+func ICMPv4TypeByNumber(typ uint8) *ICMPType {
+	idx := defaultICMPv4Types.rlock()
+	defer defaultICMPv4Types.runlock()
+	return idx.ByNumber(typ)
+}
+
+// ICMPv4ByTypeCode returns the ICMP message type and code for the specified
+// type and code numbers. If the type is known, but not the code, then the
+// returned ICMPCode is nil. If the type is unknown, then both are nil.
+func ICMPv4ByTypeCode(typ uint8, code uint8) (*ICMPType, *ICMPCode) {
+	idx := defaultICMPv4Types.rlock()
+	defer defaultICMPv4Types.runlock()
+	return idx.ByTypeCode(typ, code)
+}
+
+// ICMPv6TypeByName returns the ICMPv6 message type for the specified name,
+// such as "nd-neighbor-solicit", or nil if not defined.
+func ICMPv6TypeByName(name string) *ICMPType {
+	idx := defaultICMPv6Types.rlock()
+	defer defaultICMPv6Types.runlock()
+	return idx.ByName(name)
+}
+
+// ICMPv6TypeByNumber returns the ICMPv6 message type for the specified type
+// number, or nil if not defined.
+func ICMPv6TypeByNumber(typ uint8) *ICMPType {
+	idx := defaultICMPv6Types.rlock()
+	defer defaultICMPv6Types.runlock()
+	return idx.ByNumber(typ)
+}
+
+// ICMPv6ByTypeCode returns the ICMPv6 message type and code for the specified
+// type and code numbers. If the type is known, but not the code, then the
+// returned ICMPCode is nil. If the type is unknown, then both are nil.
+func ICMPv6ByTypeCode(typ uint8, code uint8) (*ICMPType, *ICMPCode) {
+	idx := defaultICMPv6Types.rlock()
+	defer defaultICMPv6Types.runlock()
+	return idx.ByTypeCode(typ, code)
+}
+
+// SetICMPv4Types atomically replaces the ICMPv4Types index with the specified
+// index. It is safe to call SetICMPv4Types while lookups are in flight in
+// other goroutines; these lookups finish on the old index.
+func SetICMPv4Types(i ICMPIndex) {
+	defaultICMPv4Types.set(i)
+}
+
+// SetICMPv6Types atomically replaces the ICMPv6Types index with the specified
+// index. It is safe to call SetICMPv6Types while lookups are in flight in
+// other goroutines; these lookups finish on the old index.
+func SetICMPv6Types(i ICMPIndex) {
+	defaultICMPv6Types.set(i)
+}
+
+// ICMPv4Types is the index of ICMP message types. If left to the zero value,
+// then it will be automatically initialized with the builtin definitions upon
+// first use of ICMPv4TypeByName, ICMPv4TypeByNumber, ICMPv4ByTypeCode, or
+// Protocol.ICMPTypes. This initialization is goroutine-safe.
+//
+// Directly assigning to ICMPv4Types or merging into it is not safe while
+// lookups are in flight; use SetICMPv4Types instead.
+var ICMPv4Types ICMPIndex
+
+// ICMPv6Types is the index of ICMPv6 message types. If left to the zero value,
+// then it will be automatically initialized with the builtin definitions upon
+// first use of ICMPv6TypeByName, ICMPv6TypeByNumber, ICMPv6ByTypeCode, or
+// Protocol.ICMPTypes. This initialization is goroutine-safe.
+//
+// Directly assigning to ICMPv6Types or merging into it is not safe while
+// lookups are in flight; use SetICMPv6Types instead.
+var ICMPv6Types ICMPIndex
+
+// builtinProtocolByNumber returns the builtin Protocol with the specified
+// protocol number, or nil if not defined.
+func builtinProtocolByNumber(number uint8) *Protocol {
+	for idx := range BuiltinProtocols {
+		if BuiltinProtocols[idx].Number == number {
+			return &BuiltinProtocols[idx]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("ICMP types and codes", func() {
+
+	BeforeEach(func() {
+		ICMPv4Types = ICMPIndex{}
+		ICMPv6Types = ICMPIndex{}
+		DeferCleanup(func() {
+			ICMPv4Types = ICMPIndex{}
+			ICMPv6Types = ICMPIndex{}
+		})
+	})
+
+	It("has unique builtin type numbers and names", func() {
+		for _, types := range [][]ICMPType{BuiltinICMPv4Types, BuiltinICMPv6Types} {
+			idx := NewICMPIndex(nil, types)
+			Expect(idx.Numbers).To(HaveLen(len(types)))
+			Expect(idx.Names).To(HaveLen(len(types)))
+		}
+	})
+
+	It("looks up ICMP by type and code", func() {
+		t, c := ICMPv4ByTypeCode(3, 3)
+		Expect(t).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Name":        Equal("destination-unreachable"),
+			"Description": Equal("Destination Unreachable"),
+		})))
+		Expect(c).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Code": Equal(uint8(3)),
+			"Name": Equal("port-unreachable"),
+		})))
+
+		t, c = ICMPv4ByTypeCode(8, 0)
+		Expect(t.Name).To(Equal("echo-request"))
+		Expect(c).To(BeNil())
+
+		t, c = ICMPv4ByTypeCode(123, 0)
+		Expect(t).To(BeNil())
+		Expect(c).To(BeNil())
+
+		Expect(ICMPv4TypeByNumber(4).Deprecated).To(BeTrue())
+	})
+
+	It("looks up ICMPv6 by type and code", func() {
+		t, c := ICMPv6ByTypeCode(1, 4)
+		Expect(t.Name).To(Equal("destination-unreachable"))
+		Expect(c.Name).To(Equal("port-unreachable"))
+		Expect(ICMPv6TypeByNumber(135).Name).To(Equal("nd-neighbor-solicit"))
+	})
+
+	It("looks up by type and code names", func() {
+		Expect(ICMPv4TypeByName("echo-reply").Type).To(Equal(uint8(0)))
+		Expect(ICMPv6TypeByName("nd-neighbor-solicit").Type).To(Equal(uint8(135)))
+		Expect(ICMPv6TypeByName("foobar")).To(BeNil())
+
+		t, c := ICMPv4Types.ByCodeName("host-prohibited")
+		Expect(t.Type).To(Equal(uint8(3)))
+		Expect(c.Code).To(Equal(uint8(10)))
+
+		t, c = ICMPv6Types.ByCodeName("no-error")
+		Expect(t.Type).To(Equal(uint8(160)))
+		Expect(c.Code).To(Equal(uint8(0)))
+
+		t, c = ICMPv6Types.ByCodeName("foobar")
+		Expect(t).To(BeNil())
+		Expect(c).To(BeNil())
+	})
+
+	It("ties message types to their protocols", func() {
+		protos := NewProtocolIndex(BuiltinProtocols)
+		icmp := protos.ByName("icmp").ICMPTypes()
+		Expect(icmp.Protocol.Name).To(Equal("icmp"))
+		Expect(icmp.ByName("echo-request").Type).To(Equal(uint8(8)))
+		icmpv6 := protos.ByName("ipv6-icmp").ICMPTypes()
+		Expect(icmpv6.Protocol.Name).To(Equal("ipv6-icmp"))
+		Expect(icmpv6.ByName("echo-request").Type).To(Equal(uint8(128)))
+		Expect(protos.ByName("tcp").ICMPTypes()).To(BeNil())
+		Expect(protos.ByName("foobar").ICMPTypes()).To(BeNil())
+	})
+
+	It("replaces the ICMP message type indices", func() {
+		SetICMPv4Types(NewICMPIndex(nil, []ICMPType{{Type: 8, Name: "ping"}}))
+		Expect(ICMPv4TypeByNumber(8).Name).To(Equal("ping"))
+		Expect(ICMPv4TypeByName("echo-request")).To(BeNil())
+		SetICMPv6Types(NewICMPIndex(nil, []ICMPType{{Type: 128, Name: "ping6"}}))
+		Expect(ICMPv6TypeByNumber(128).Name).To(Equal("ping6"))
+		Expect((&Protocol{Number: 58}).ICMPTypes().ByName("ping6")).NotTo(BeNil())
+	})
+
+	It("merges", func() {
+		idx := NewICMPIndex(nil, BuiltinICMPv4Types)
+		idx.MergeIndex(NewICMPIndex(nil, []ICMPType{
+			{Type: 200, Name: "foo"},
+			{Type: 0, Name: "pong"},
+		}))
+		Expect(idx.ByNumber(0).Name).To(Equal("pong"))
+		Expect(idx.ByName("echo-reply").Type).To(Equal(uint8(0)))
+		Expect(slices.Collect(idx.All())).To(HaveLen(len(BuiltinICMPv4Types) + 2))
+
+		var zero ICMPIndex
+		zero.Merge([]ICMPType{{Type: 1, Name: "bar"}})
+		Expect(zero.ByNumber(1)).NotTo(BeNil())
+	})
+
+})
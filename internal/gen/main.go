@@ -15,9 +15,14 @@
 package main
 
 import (
+	"compress/gzip"
 	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"flag"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"strings"
 	"text/template"
@@ -31,40 +36,82 @@ const (
 	debianGitlabUrl    = "https://salsa.debian.org"
 	debianGitlabAPIUrl = debianGitlabUrl + "/api/v4"
 	netbaseProjectID   = "md/netbase"
+
+	iproute2GitUrl = "https://git.kernel.org/pub/scm/network/iproute2/iproute2.git"
+	iproute2Tag    = "v6.11.0" // upstream release to take the iproute2 tables from.
+
+	ieeeOUIUrl = "https://standards-oui.ieee.org"
 )
 
 var headerTemplate = template.Must(template.New("").Parse(`// Code generated by go generate. DO NOT EDIT.
 
-// Generated from Debian project {{.Project}} at {{.Url}}
+// Generated from {{.Origin}}
 // At {{.Timestamp.Format "2006-01-02T15:04:05Z07:00"}}
 // File {{.File}}
+{{- if .Commit }}
 // Commit {{.Commit}}
+{{- end }}
 
 package netdb
 
 `))
 
-func genHeader(w io.Writer, filename string, commit string) {
+func genHeader(w io.Writer, f netbaseFile) {
 	if err := headerTemplate.Execute(w, struct {
-		Project   string
-		Url       string
+		Origin    string
 		Timestamp time.Time
 		File      string
 		Commit    string
 	}{
-		Project:   netbaseProjectID,
-		Url:       debianGitlabUrl,
+		Origin:    f.Origin,
 		Timestamp: time.Now().UTC(),
-		File:      filename,
-		Commit:    commit,
+		File:      f.Filename,
+		Commit:    f.Commit,
 	}); err != nil {
 		panic(err)
 	}
 }
 
+// netbaseFile is a netbase file fetched from the Debian md/netbase project.
+type netbaseFile struct {
+	Origin   string    // where the file came from.
+	Filename string    // file name, such as "etc/services".
+	Commit   string    // latest commit ID.
+	Content  io.Reader // file contents.
+}
+
+// fetcher returns the specified netbase file, such as "etc/services".
+type fetcher func(filename string) netbaseFile
+
+// debianFetcher returns a fetcher for the netbase files from the Debian
+// md/netbase project.
+func debianFetcher(git *gitlab.Client) fetcher {
+	return func(filename string) netbaseFile {
+		fmt.Printf("fetching %s from md/netbase Debian repository...\n", filename)
+		f, _, err := git.RepositoryFiles.GetFile(
+			netbaseProjectID,
+			filename,
+			&gitlab.GetFileOptions{
+				Ref: gitlab.String("master"),
+			})
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("latest commit ID: %s\n", f.LastCommitID)
+		return netbaseFile{
+			Origin:   fmt.Sprintf("Debian project %s at %s", netbaseProjectID, debianGitlabUrl),
+			Filename: filename,
+			Commit:   f.LastCommitID,
+			Content: base64.NewDecoder(
+				base64.StdEncoding,
+				strings.NewReader(f.Content)),
+		}
+	}
+}
+
 // Generate builtin_ethertypes.go based on a freshly fetched /etc/ethertypes
 // from the Debian md/netbase project.
-func genEtherTypes(git *gitlab.Client) {
+func genEtherTypes(fetch fetcher) {
 	ethertypesTemplate := template.Must(template.New("").Parse(`var BuiltinEtherTypes []EtherType = builtinEtherTypes
 var builtinEtherTypes = []EtherType{
 	{{- range . }}
@@ -77,22 +124,8 @@ var builtinEtherTypes = []EtherType{
 	}
 	`))
 
-	fmt.Printf("fetching etc/ethertypes from md/netbase Debian repository...\n")
-	f, resp, err := git.RepositoryFiles.GetFile(
-		netbaseProjectID,
-		"etc/ethertypes",
-		&gitlab.GetFileOptions{
-			Ref: gitlab.String("master"),
-		})
-	if err != nil {
-		panic(err)
-	}
-	fmt.Printf("latest commit ID: %s\n", f.LastCommitID)
-	defer resp.Body.Close()
-	ethertypes, err := netdb.ParseEtherTypes(
-		base64.NewDecoder(
-			base64.StdEncoding,
-			strings.NewReader(f.Content)))
+	f := fetch("etc/ethertypes")
+	ethertypes, err := netdb.ParseEtherTypes(f.Content)
 	if err != nil {
 		panic(err)
 	}
@@ -107,20 +140,58 @@ var builtinEtherTypes = []EtherType{
 		panic(err)
 	}
 	defer gof.Close()
-	genHeader(gof, "etc/ethertypes", f.LastCommitID)
+	genHeader(gof, f)
 	if err := ethertypesTemplate.Execute(gof, ethertypes); err != nil {
 		panic(err)
 	}
 	fmt.Printf("done\n")
 }
 
+// Generate builtin_rpc.go based on a freshly fetched /etc/rpc from the Debian
+// md/netbase project.
+func genRPC(fetch fetcher) {
+	rpcTemplate := template.Must(template.New("").Parse(`var BuiltinRPCPrograms []RPCProgram = builtinRPCPrograms
+var builtinRPCPrograms = []RPCProgram{
+	{{- range . }}
+		{ Name: {{ printf "%q" .Name }}, Number: {{ printf "%d" .Number }}, Comment: {{ printf "%q" .Comment }}, Aliases: []string{
+				{{- range .Aliases -}}
+					{{- printf "%q" . }},
+				{{- end -}}
+			} },
+	{{- end }}
+	}
+	`))
+
+	f := fetch("etc/rpc")
+	programs, err := netdb.ParseRPC(f.Content)
+	if err != nil {
+		panic(err)
+	}
+	if len(programs) < 2 {
+		panic("not enough RPC programs found; invalid /etc/rpc?")
+	}
+	fmt.Printf("%d RPC programs found\n", len(programs))
+
+	fmt.Printf("generating builtin_rpc.go...\n")
+	gof, err := os.Create("builtin_rpc.go")
+	if err != nil {
+		panic(err)
+	}
+	defer gof.Close()
+	genHeader(gof, f)
+	if err := rpcTemplate.Execute(gof, programs); err != nil {
+		panic(err)
+	}
+	fmt.Printf("done\n")
+}
+
 // Generate builtin/protocols.go based on a freshly fetched /etc/protocols from
 // the Debian md/netbase project.
-func genProtocols(git *gitlab.Client) []netdb.Protocol {
+func genProtocols(fetch fetcher) []netdb.Protocol {
 	protocolsTemplate := template.Must(template.New("").Parse(`var BuiltinProtocols []Protocol = builtinProtocols
 var builtinProtocols = []Protocol{
 	{{- range . }}
-		{ Name: {{ printf "%q" .Name }}, Number: {{ printf "%d" .Number }}, Aliases: []string{
+		{ Name: {{ printf "%q" .Name }}, Number: {{ printf "%d" .Number }}, Comment: {{ printf "%q" .Comment }}, Aliases: []string{
 				{{- range .Aliases -}}
 					{{- printf "%q" . }},
 				{{- end -}}
@@ -129,22 +200,8 @@ var builtinProtocols = []Protocol{
 	}
 	`))
 
-	fmt.Printf("fetching etc/protocols from md/netbase Debian repository...\n")
-	f, resp, err := git.RepositoryFiles.GetFile(
-		netbaseProjectID,
-		"etc/protocols",
-		&gitlab.GetFileOptions{
-			Ref: gitlab.String("master"),
-		})
-	if err != nil {
-		panic(err)
-	}
-	fmt.Printf("latest commit ID: %s\n", f.LastCommitID)
-	defer resp.Body.Close()
-	protocols, err := netdb.ParseProtocols(
-		base64.NewDecoder(
-			base64.StdEncoding,
-			strings.NewReader(f.Content)))
+	f := fetch("etc/protocols")
+	protocols, err := netdb.ParseProtocols(f.Content)
 	if err != nil {
 		panic(err)
 	}
@@ -159,7 +216,7 @@ var builtinProtocols = []Protocol{
 		panic(err)
 	}
 	defer gof.Close()
-	genHeader(gof, "etc/protocols", f.LastCommitID)
+	genHeader(gof, f)
 	if err := protocolsTemplate.Execute(gof, protocols); err != nil {
 		panic(err)
 	}
@@ -167,7 +224,7 @@ var builtinProtocols = []Protocol{
 	return protocols
 }
 
-func genServices(git *gitlab.Client, protos []netdb.Protocol) {
+func genServices(fetch fetcher, protos []netdb.Protocol) {
 	protoindices := map[string]int{}
 	for idx, proto := range protos {
 		protoindices[proto.Name] = idx
@@ -185,7 +242,7 @@ func genServices(git *gitlab.Client, protos []netdb.Protocol) {
 	}).Parse(`var BuiltinServices []Service = builtinServices
 var builtinServices = []Service{
 	{{- range . }}
-		{ Name: {{ printf "%q" .Name }}, Port: {{ printf "%d" .Port }}, ProtocolName: {{ printf "%q" .ProtocolName }}, Protocol: {{ protoref .ProtocolName }} , Aliases: []string{
+		{ Name: {{ printf "%q" .Name }}, Port: {{ printf "%d" .Port }},{{ if .LastPort }} LastPort: {{ printf "%d" .LastPort }},{{ end }} ProtocolName: {{ printf "%q" .ProtocolName }}, Protocol: {{ protoref .ProtocolName }} , Comment: {{ printf "%q" .Comment }}, Aliases: []string{
 				{{- range .Aliases -}}
 					{{- printf "%q" . }},
 				{{- end -}}
@@ -194,23 +251,8 @@ var builtinServices = []Service{
 	}
 	`))
 
-	fmt.Printf("fetching etc/services from md/netbase Debian repository...\n")
-	f, resp, err := git.RepositoryFiles.GetFile(
-		netbaseProjectID,
-		"etc/services",
-		&gitlab.GetFileOptions{
-			Ref: gitlab.String("master"),
-		})
-	if err != nil {
-		panic(err)
-	}
-	fmt.Printf("latest commit ID: %s\n", f.LastCommitID)
-	defer resp.Body.Close()
-	services, err := netdb.ParseServices(
-		base64.NewDecoder(
-			base64.StdEncoding,
-			strings.NewReader(f.Content)),
-		netdb.NewProtocolIndex(protos))
+	f := fetch("etc/services")
+	services, err := netdb.ParseServices(f.Content, netdb.NewProtocolIndex(protos))
 	if err != nil {
 		panic(err)
 	}
@@ -225,21 +267,197 @@ var builtinServices = []Service{
 		panic(err)
 	}
 	defer gof.Close()
-	genHeader(gof, "etc/services", f.LastCommitID)
+	genHeader(gof, f)
 	if err := servicesTemplate.Execute(gof, services); err != nil {
 		panic(err)
 	}
 	fmt.Printf("done\n")
 }
 
-// Fetch /etc/protocols and /etc/services from the netbase package of the Debian
-// project and generate the static "builtin" go files from its contents.
+// Generate builtin_iproute2.go based on the iproute2 table files of the pinned
+// upstream iproute2 release, independent of any distribution's modifications.
+func genIPRoute2() {
+	routeNamesTemplate := template.Must(template.New("").Parse(`var Builtin{{ .Var }} []RouteName = builtin{{ .Var }}
+var builtin{{ .Var }} = []RouteName{
+	{{- range .Names }}
+		{ Name: {{ printf "%q" .Name }}, Number: {{ printf "%d" .Number }}, Comment: {{ printf "%q" .Comment }} },
+	{{- end }}
+	}
+
+`))
+
+	tables := []struct {
+		Var  string
+		File string
+	}{
+		{Var: "RouteProtocols", File: "rt_protos"},
+		{Var: "RouteTables", File: "rt_tables"},
+		{Var: "RouteScopes", File: "rt_scopes"},
+		{Var: "RouteRealms", File: "rt_realms"},
+		{Var: "RouteDSFields", File: "rt_dsfield"},
+	}
+	files := []string{}
+	for _, table := range tables {
+		files = append(files, table.File)
+	}
+
+	tableNames := make([][]netdb.RouteName, len(tables))
+	for idx, table := range tables {
+		names := fetchIPRoute2Table(table.File)
+		if len(names) < 1 {
+			panic(fmt.Sprintf("no entries found; invalid %s?", table.File))
+		}
+		fmt.Printf("%d %s entries found\n", len(names), table.File)
+		tableNames[idx] = names
+	}
+
+	fmt.Printf("generating builtin_iproute2.go...\n")
+	gof, err := os.Create("builtin_iproute2.go")
+	if err != nil {
+		panic(err)
+	}
+	defer gof.Close()
+	genHeader(gof, netbaseFile{
+		Origin:   fmt.Sprintf("iproute2 %s at %s", iproute2Tag, iproute2GitUrl),
+		Filename: "etc/iproute2/{" + strings.Join(files, ",") + "}",
+	})
+	for idx, table := range tables {
+		if err := routeNamesTemplate.Execute(gof, struct {
+			Var   string
+			Names []netdb.RouteName
+		}{
+			Var:   table.Var,
+			Names: tableNames[idx],
+		}); err != nil {
+			panic(err)
+		}
+	}
+	fmt.Printf("done\n")
+}
+
+// fetchIPRoute2Table fetches the named table file, such as "rt_tables", from
+// the pinned upstream iproute2 release and returns its entries.
+func fetchIPRoute2Table(name string) []netdb.RouteName {
+	fmt.Printf("fetching etc/iproute2/%s from iproute2 %s...\n", name, iproute2Tag)
+	resp, err := http.Get(iproute2GitUrl + "/plain/etc/iproute2/" + name + "?h=" + iproute2Tag)
+	if err != nil {
+		panic(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		panic(fmt.Sprintf("fetching iproute2 %s failed: %s", name, resp.Status))
+	}
+	names, err := netdb.ParseRouteNamesWithOptions(resp.Body, netdb.ParseOptions{Mode: netdb.ParseStrict})
+	if err != nil {
+		panic(err)
+	}
+	return names
+}
+
+// genOUIs fetches the IEEE MA-L, MA-M, and MA-S registries and writes their
+// assignments as a gzip-compressed CSV table that gets embedded into the
+// package, together with the Go file embedding it.
+func genOUIs() {
+	registries := []string{"oui/oui.csv", "oui28/mam.csv", "oui36/oui36.csv"}
+
+	ouis := []netdb.OUI{}
+	for _, registry := range registries {
+		ouis = append(ouis, fetchIEEERegistry(registry)...)
+	}
+	if len(ouis) < 1 {
+		panic("no IEEE registry entries found")
+	}
+
+	fmt.Printf("generating builtin_oui_ieee.csv.gz...\n")
+	csvf, err := os.Create("builtin_oui_ieee.csv.gz")
+	if err != nil {
+		panic(err)
+	}
+	defer csvf.Close()
+	zw, err := gzip.NewWriterLevel(csvf, gzip.BestCompression)
+	if err != nil {
+		panic(err)
+	}
+	cw := csv.NewWriter(zw)
+	_ = cw.Write([]string{"Registry", "Assignment", "Organization Name"})
+	for _, oui := range ouis {
+		assignment := strings.ToUpper(hex.EncodeToString(oui.Prefix.Addr[:]))[:oui.Prefix.Bits/4]
+		_ = cw.Write([]string{oui.Registry, assignment, oui.Organization})
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		panic(err)
+	}
+	if err := zw.Close(); err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("generating builtin_oui_ieee.go...\n")
+	gof, err := os.Create("builtin_oui_ieee.go")
+	if err != nil {
+		panic(err)
+	}
+	defer gof.Close()
+	genHeader(gof, netbaseFile{
+		Origin:   "IEEE registries at " + ieeeOUIUrl,
+		Filename: "{" + strings.Join(registries, ",") + "}",
+	})
+	fmt.Fprint(gof, `import _ "embed"
+
+// builtinIEEEOUIsCSV is the gzip-compressed CSV table of the MAC address
+// blocks assigned in the IEEE MA-L, MA-M, and MA-S registries.
+//
+//go:embed builtin_oui_ieee.csv.gz
+var builtinIEEEOUIsCSV []byte
+`)
+	fmt.Printf("done\n")
+}
+
+// fetchIEEERegistry fetches the specified IEEE registry CSV file, such as
+// "oui/oui.csv", and returns its assignments.
+func fetchIEEERegistry(name string) []netdb.OUI {
+	fmt.Printf("fetching %s from %s...\n", name, ieeeOUIUrl)
+	resp, err := http.Get(ieeeOUIUrl + "/" + name)
+	if err != nil {
+		panic(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		panic(fmt.Sprintf("fetching IEEE registry %s failed: %s", name, resp.Status))
+	}
+	ouis, err := netdb.ParseIEEEOUICSV(resp.Body)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%d %s entries found\n", len(ouis), name)
+	return ouis
+}
+
+// Fetch /etc/protocols, /etc/services, /etc/ethertypes, and /etc/rpc from the
+// netbase package of the Debian project and generate the static "builtin" go
+// files from its contents. When run with the "-iproute2" flag, generate the
+// builtin iproute2 tables from upstream iproute2 instead. When run with the
+// "-oui" flag, generate the builtin IEEE registry table instead.
 func main() {
+	iproute2 := flag.Bool("iproute2", false, "generate builtin iproute2 tables from upstream iproute2")
+	oui := flag.Bool("oui", false, "generate builtin OUI table from the IEEE registries")
+	flag.Parse()
+	if *iproute2 {
+		genIPRoute2()
+		return
+	}
+	if *oui {
+		genOUIs()
+		return
+	}
+
 	debgit, err := gitlab.NewClient("", gitlab.WithBaseURL(debianGitlabAPIUrl))
 	if err != nil {
 		panic(err)
 	}
-	genEtherTypes(debgit)
-	protocols := genProtocols(debgit)
-	genServices(debgit, protocols)
+	fetch := debianFetcher(debgit)
+	genEtherTypes(fetch)
+	genRPC(fetch)
+	protocols := genProtocols(fetch)
+	genServices(fetch, protocols)
 }
@@ -0,0 +1,502 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RouteName describes a "number name" mapping from one of the iproute2 table
+// files, such as the route protocol "kernel" with number 2 from rt_protos, or
+// the route table "main" with number 254 from rt_tables.
+type RouteName struct {
+	Name    string // Name, such as "kernel" or "main".
+	Number  uint32 // Number value.
+	Comment string // Entry comment, if present.
+}
+
+// RouteNameIndex indexes the names and numbers from an iproute2 table file,
+// such as rt_protos or rt_tables.
+//
+// When multiple entries share the same name or number, the Precedence rule
+// decides which entry becomes the primary one. The Precedence rule needs to be
+// set before merging any entries.
+type RouteNameIndex struct {
+	Names      map[string]*RouteName // Index by name.
+	Numbers    map[uint32]*RouteName // Index by number.
+	Precedence Precedence            // Rule for picking the primary entry.
+
+	entries []*RouteName // all merged entries in definition order.
+}
+
+// IPRoute2Dirs lists the directories with the iproute2 table files, in the
+// order of decreasing precedence.
+var IPRoute2Dirs = []string{"/etc/iproute2", "/usr/share/iproute2"}
+
+// NewRouteNameIndex returns a RouteNameIndex object initialized with the
+// specified entries.
+func NewRouteNameIndex(names []RouteName) RouteNameIndex {
+	i := RouteNameIndex{
+		Names:   map[string]*RouteName{},
+		Numbers: map[uint32]*RouteName{},
+	}
+	i.Merge(names)
+	return i
+}
+
+// LoadRouteNames returns a RouteNameIndex object initialized from the
+// definitions in the named file.
+func LoadRouteNames(name string) (RouteNameIndex, error) {
+	return LoadRouteNamesWithOptions(name, ParseOptions{})
+}
+
+// LoadRouteNamesWithOptions returns a RouteNameIndex object initialized from
+// the definitions in the named file, parsing it as specified by the options.
+// If the options don't specify a file name, then the specified name is used.
+// In ParseCollectAll mode, the returned index contains all well-formed
+// definitions, even when a ParseErrors error is returned.
+func LoadRouteNamesWithOptions(name string, opts ParseOptions) (RouteNameIndex, error) {
+	i := NewRouteNameIndex(nil)
+	err := i.mergeFile(name, opts)
+	return i, err
+}
+
+// LoadIPRoute2 returns a RouteNameIndex object initialized from the named
+// iproute2 table file, such as "rt_tables", located in the IPRoute2Dirs.
+//
+// Similar to iproute2, LoadIPRoute2 reads the table file from the first of the
+// IPRoute2Dirs where it exists. It then merges the "*.conf" files from the
+// table's drop-in directories, such as "rt_tables.d", in all IPRoute2Dirs,
+// starting with the directory of least precedence. A drop-in file shadows
+// drop-in files with the same name in directories of less precedence. Drop-in
+// files within the same directory are merged in lexical order.
+//
+// LoadIPRoute2 returns an error wrapping fs.ErrNotExist if there is neither a
+// table file nor any drop-in file.
+func LoadIPRoute2(name string) (RouteNameIndex, error) {
+	return loadIPRoute2(IPRoute2Dirs, name, ParseOptions{})
+}
+
+// LoadIPRoute2WithOptions is like LoadIPRoute2, but parses the table and
+// drop-in files as specified by the options. The Filename in the options is
+// ignored. In ParseCollectAll mode, the returned index contains all
+// well-formed definitions from all files, even when a ParseErrors error
+// listing the malformed lines of all files is returned.
+func LoadIPRoute2WithOptions(name string, opts ParseOptions) (RouteNameIndex, error) {
+	return loadIPRoute2(IPRoute2Dirs, name, opts)
+}
+
+func loadIPRoute2(dirs []string, name string, opts ParseOptions) (RouteNameIndex, error) {
+	opts.Filename = ""
+	i := NewRouteNameIndex(nil)
+	var perrs ParseErrors
+	// merge merges the named file into the index, collecting parse errors in
+	// ParseCollectAll mode instead of stopping at the first file with errors.
+	merge := func(name string) error {
+		err := i.mergeFile(name, opts)
+		var errs ParseErrors
+		if opts.Mode == ParseCollectAll && errors.As(err, &errs) {
+			perrs = append(perrs, errs...)
+			return nil
+		}
+		return err
+	}
+
+	found := false
+	for _, dir := range dirs {
+		err := merge(filepath.Join(dir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return i, err
+		}
+		found = true
+		break
+	}
+
+	dropins := map[string]struct{}{}
+	var dirfiles [][]string
+	for _, dir := range dirs {
+		matches, _ := filepath.Glob(filepath.Join(dir, name+".d", "*.conf"))
+		files := []string{}
+		for _, match := range matches {
+			if _, ok := dropins[filepath.Base(match)]; ok {
+				continue
+			}
+			dropins[filepath.Base(match)] = struct{}{}
+			files = append(files, match)
+		}
+		dirfiles = append(dirfiles, files)
+	}
+	for _, files := range slices.Backward(dirfiles) {
+		for _, file := range files {
+			if err := merge(file); err != nil {
+				return i, err
+			}
+			found = true
+		}
+	}
+
+	if !found {
+		return i, fmt.Errorf("no iproute2 %s table in %s: %w",
+			name, strings.Join(dirs, ", "), fs.ErrNotExist)
+	}
+	if len(perrs) != 0 {
+		return i, perrs
+	}
+	return i, nil
+}
+
+// mergeFile merges the definitions in the named file into the index, parsing
+// the file as specified by the options.
+func (i *RouteNameIndex) mergeFile(name string, opts ParseOptions) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if opts.Filename == "" {
+		opts.Filename = name
+	}
+	names, err := ParseRouteNamesWithOptions(f, opts)
+	if names != nil {
+		i.Merge(names)
+	}
+	return err
+}
+
+// Merge a list of RouteName descriptions into the current index, potentially
+// overriding existing entries in the index in case of duplicates, depending on
+// the index's Precedence rule.
+func (i *RouteNameIndex) Merge(names []RouteName) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for idx, name := range names {
+		setPrimary(i.Names, name.Name, &names[idx], firstWins)
+		setPrimary(i.Numbers, name.Number, &names[idx], firstWins)
+		i.entries = append(i.entries, &names[idx])
+	}
+}
+
+// MergeIndex merges another RouteNameIndex into the current index, potentially
+// overriding existing entries in case of duplicates, depending on the index's
+// Precedence rule.
+func (i *RouteNameIndex) MergeIndex(rni RouteNameIndex) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for name, entry := range rni.Names {
+		setPrimary(i.Names, name, entry, firstWins)
+	}
+	for number, entry := range rni.Numbers {
+		setPrimary(i.Numbers, number, entry, firstWins)
+	}
+	i.entries = append(i.entries, rni.entries...)
+}
+
+// init initializes the index maps, if not already done.
+func (i *RouteNameIndex) init() {
+	if i.Names == nil {
+		i.Names = map[string]*RouteName{}
+	}
+	if i.Numbers == nil {
+		i.Numbers = map[uint32]*RouteName{}
+	}
+}
+
+// ByName returns the RouteName for the specified name, or nil if not found.
+func (i *RouteNameIndex) ByName(name string) *RouteName {
+	return i.Names[name]
+}
+
+// ByNumber returns the RouteName for the specified number, or nil if not
+// found.
+func (i *RouteNameIndex) ByNumber(number uint32) *RouteName {
+	return i.Numbers[number]
+}
+
+// AllByName returns all entries with the specified name in the order of their
+// original definitions, including entries overridden by later merges.
+func (i *RouteNameIndex) AllByName(name string) []*RouteName {
+	var names []*RouteName
+	for entry := range i.All() {
+		if entry.Name == name {
+			names = append(names, entry)
+		}
+	}
+	return names
+}
+
+// AllByNumber returns all entries with the specified number in the order of
+// their original definitions, including entries overridden by later merges.
+func (i *RouteNameIndex) AllByNumber(number uint32) []*RouteName {
+	var names []*RouteName
+	for entry := range i.All() {
+		if entry.Number == number {
+			names = append(names, entry)
+		}
+	}
+	return names
+}
+
+// All returns an iterator over all entries merged into this index, in the
+// order of their original definitions, including entries that have been
+// overridden by later merges.
+func (i *RouteNameIndex) All() iter.Seq[*RouteName] {
+	return allOf(i.entries)
+}
+
+// ParseRouteNames parses "number name" definitions of an iproute2 table file,
+// such as rt_protos or rt_tables, from the given Reader and returns them as a
+// list of RouteName(s). Incomplete definitions are silently skipped, while
+// invalid numbers result in an error.
+func ParseRouteNames(r io.Reader) ([]RouteName, error) {
+	return ParseRouteNamesWithOptions(r, ParseOptions{})
+}
+
+// ParseRouteNamesWithOptions parses "number name" definitions of an iproute2
+// table file from the given Reader as specified by the options and returns
+// them as a list of RouteName(s). Same as iproute2, numbers can be decimal,
+// hexadecimal with a "0x" prefix, or octal with a "0" prefix, and any fields
+// following the name are ignored.
+func ParseRouteNamesWithOptions(r io.Reader, opts ParseOptions) ([]RouteName, error) {
+	names := []RouteName{}
+	lp := lineParser{opts: opts}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lp.next()
+		line := strings.TrimSpace(scanner.Text())
+		definition, comment := splitComment(line)
+		fields := strings.Fields(definition)
+		if len(fields) == 0 {
+			continue // skip empty lines and comment lines.
+		}
+		if len(fields) < 2 {
+			if err := lp.malformed(line, "missing name", false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		number, err := parseRouteNumber(fields[0])
+		if err != nil {
+			if err := lp.malformed(line, "invalid number", true); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		names = append(names, RouteName{
+			Name:    fields[1],
+			Number:  uint32(number),
+			Comment: comment,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return names, lp.err()
+}
+
+// parseRouteNumber parses a number the same way as the C library's strtoul
+// with base 0 does: hexadecimal with a "0x" or "0X" prefix, octal with a
+// leading "0", and decimal otherwise. In contrast to strconv.ParseUint with
+// base 0, it doesn't accept "0b" and "0o" prefixes, nor underscores.
+func parseRouteNumber(s string) (uint64, error) {
+	base := 10
+	switch {
+	case len(s) > 2 && (s[:2] == "0x" || s[:2] == "0X"):
+		s, base = s[2:], 16
+	case len(s) > 1 && s[0] == '0':
+		s, base = s[1:], 8
+	}
+	return strconv.ParseUint(s, base, 32)
+}
+
+// RouteProtocolByName returns the route protocol details for the specified
+// name, such as "kernel" or "bgp", or nil if not defined.
+func RouteProtocolByName(name string) *RouteName {
+	idx := defaultRouteProtocols.rlock()
+	defer defaultRouteProtocols.runlock()
+	return idx.ByName(name)
+}
+
+// RouteProtocolByNumber returns the route protocol details for the specified
+// number, or nil if not defined.
+func RouteProtocolByNumber(number uint32) *RouteName {
+	idx := defaultRouteProtocols.rlock()
+	defer defaultRouteProtocols.runlock()
+	return idx.ByNumber(number)
+}
+
+// SetRouteProtocols atomically replaces the route protocols index with the
+// specified index, such as returned by LoadIPRoute2("rt_protos"). It is safe to
+// call SetRouteProtocols while lookups are in flight in other goroutines; these
+// lookups finish on the old index.
+func SetRouteProtocols(i RouteNameIndex) {
+	defaultRouteProtocols.set(i)
+}
+
+// RouteProtocols is the index of route protocol names and numbers from
+// iproute2's rt_protos. If left to the zero value, then it will be
+// automatically initialized with the builtin definitions upon first use of
+// RouteProtocolByName or RouteProtocolByNumber. This initialization is
+// goroutine-safe.
+//
+// Directly assigning to RouteProtocols or merging into it is not safe while
+// lookups are in flight; use SetRouteProtocols instead.
+var RouteProtocols RouteNameIndex
+
+// RouteTableByName returns the route table details for the specified name, such
+// as "main" or "local", or nil if not defined.
+func RouteTableByName(name string) *RouteName {
+	idx := defaultRouteTables.rlock()
+	defer defaultRouteTables.runlock()
+	return idx.ByName(name)
+}
+
+// RouteTableByNumber returns the route table details for the specified number,
+// or nil if not defined.
+func RouteTableByNumber(number uint32) *RouteName {
+	idx := defaultRouteTables.rlock()
+	defer defaultRouteTables.runlock()
+	return idx.ByNumber(number)
+}
+
+// SetRouteTables atomically replaces the route tables index with the specified
+// index, such as returned by LoadIPRoute2("rt_tables"). It is safe to call
+// SetRouteTables while lookups are in flight in other goroutines; these lookups
+// finish on the old index.
+func SetRouteTables(i RouteNameIndex) {
+	defaultRouteTables.set(i)
+}
+
+// RouteTables is the index of route table names and numbers from iproute2's
+// rt_tables. If left to the zero value, then it will be automatically
+// initialized with the builtin definitions upon first use of RouteTableByName
+// or RouteTableByNumber. This initialization is goroutine-safe.
+//
+// Directly assigning to RouteTables or merging into it is not safe while
+// lookups are in flight; use SetRouteTables instead.
+var RouteTables RouteNameIndex
+
+// RouteScopeByName returns the route scope details for the specified name, such
+// as "link" or "host", or nil if not defined.
+func RouteScopeByName(name string) *RouteName {
+	idx := defaultRouteScopes.rlock()
+	defer defaultRouteScopes.runlock()
+	return idx.ByName(name)
+}
+
+// RouteScopeByNumber returns the route scope details for the specified number,
+// or nil if not defined.
+func RouteScopeByNumber(number uint32) *RouteName {
+	idx := defaultRouteScopes.rlock()
+	defer defaultRouteScopes.runlock()
+	return idx.ByNumber(number)
+}
+
+// SetRouteScopes atomically replaces the route scopes index with the specified
+// index, such as returned by LoadIPRoute2("rt_scopes"). It is safe to call
+// SetRouteScopes while lookups are in flight in other goroutines; these lookups
+// finish on the old index.
+func SetRouteScopes(i RouteNameIndex) {
+	defaultRouteScopes.set(i)
+}
+
+// RouteScopes is the index of route scope names and numbers from iproute2's
+// rt_scopes. If left to the zero value, then it will be automatically
+// initialized with the builtin definitions upon first use of RouteScopeByName
+// or RouteScopeByNumber. This initialization is goroutine-safe.
+//
+// Directly assigning to RouteScopes or merging into it is not safe while
+// lookups are in flight; use SetRouteScopes instead.
+var RouteScopes RouteNameIndex
+
+// RouteRealmByName returns the route realm details for the specified name, such
+// as "cosmos", or nil if not defined.
+func RouteRealmByName(name string) *RouteName {
+	idx := defaultRouteRealms.rlock()
+	defer defaultRouteRealms.runlock()
+	return idx.ByName(name)
+}
+
+// RouteRealmByNumber returns the route realm details for the specified number,
+// or nil if not defined.
+func RouteRealmByNumber(number uint32) *RouteName {
+	idx := defaultRouteRealms.rlock()
+	defer defaultRouteRealms.runlock()
+	return idx.ByNumber(number)
+}
+
+// SetRouteRealms atomically replaces the route realms index with the specified
+// index, such as returned by LoadIPRoute2("rt_realms"). It is safe to call
+// SetRouteRealms while lookups are in flight in other goroutines; these lookups
+// finish on the old index.
+func SetRouteRealms(i RouteNameIndex) {
+	defaultRouteRealms.set(i)
+}
+
+// RouteRealms is the index of route realm names and numbers from iproute2's
+// rt_realms. If left to the zero value, then it will be automatically
+// initialized with the builtin definitions upon first use of RouteRealmByName
+// or RouteRealmByNumber. This initialization is goroutine-safe.
+//
+// Directly assigning to RouteRealms or merging into it is not safe while
+// lookups are in flight; use SetRouteRealms instead.
+var RouteRealms RouteNameIndex
+
+// RouteDSFieldByName returns the DS field details for the specified name, such
+// as "EF" or "AF11", or nil if not defined.
+func RouteDSFieldByName(name string) *RouteName {
+	idx := defaultRouteDSFields.rlock()
+	defer defaultRouteDSFields.runlock()
+	return idx.ByName(name)
+}
+
+// RouteDSFieldByNumber returns the DS field details for the specified number,
+// or nil if not defined.
+func RouteDSFieldByNumber(number uint32) *RouteName {
+	idx := defaultRouteDSFields.rlock()
+	defer defaultRouteDSFields.runlock()
+	return idx.ByNumber(number)
+}
+
+// SetRouteDSFields atomically replaces the DS fields index with the specified
+// index, such as returned by LoadIPRoute2("rt_dsfield"). It is safe to call
+// SetRouteDSFields while lookups are in flight in other goroutines; these
+// lookups finish on the old index.
+func SetRouteDSFields(i RouteNameIndex) {
+	defaultRouteDSFields.set(i)
+}
+
+// RouteDSFields is the index of DS field names and numbers from iproute2's
+// rt_dsfield. If left to the zero value, then it will be automatically
+// initialized with the builtin definitions upon first use of RouteDSFieldByName
+// or RouteDSFieldByNumber. This initialization is goroutine-safe.
+//
+// Directly assigning to RouteDSFields or merging into it is not safe while
+// lookups are in flight; use SetRouteDSFields instead.
+var RouteDSFields RouteNameIndex
@@ -0,0 +1,198 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("iproute2 tables", func() {
+
+	Context("parsing descriptions", func() {
+
+		It("returns correct descriptions", func() {
+			n, err := ParseRouteNames(strings.NewReader(`
+# A comment
+254	main
+0xB8	EF	# expedited forwarding
+010	octal extra fields
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(HaveExactElements(
+				MatchAllFields(Fields{
+					"Name":    Equal("main"),
+					"Number":  Equal(uint32(254)),
+					"Comment": BeEmpty(),
+				}),
+				MatchAllFields(Fields{
+					"Name":    Equal("EF"),
+					"Number":  Equal(uint32(0xb8)),
+					"Comment": Equal("expedited forwarding"),
+				}),
+				MatchAllFields(Fields{
+					"Name":    Equal("octal"),
+					"Number":  Equal(uint32(8)),
+					"Comment": BeEmpty(),
+				}),
+			))
+		})
+
+		It("skips incomplete definitions", func() {
+			n, err := ParseRouteNames(strings.NewReader("42\n254 main\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(HaveExactElements(HaveField("Name", "main")))
+		})
+
+		It("rejects invalid numbers", func() {
+			_, err := ParseRouteNames(strings.NewReader("main 254\n"))
+			Expect(err).To(MatchError(ContainSubstring("invalid number")))
+
+			for _, number := range []string{"0b101", "0o17", "1_000", "0x", "08", "+1", "-1", "4294967296"} {
+				_, err := ParseRouteNames(strings.NewReader(number + " foo\n"))
+				Expect(err).To(MatchError(ContainSubstring("invalid number")), number)
+			}
+		})
+
+	})
+
+	Context("loading", func() {
+
+		BeforeEach(func() {
+			dirs := IPRoute2Dirs
+			IPRoute2Dirs = []string{"test/iproute2/etc", "test/iproute2/usr"}
+			DeferCleanup(func() { IPRoute2Dirs = dirs })
+		})
+
+		It("loads a single file", func() {
+			idx, err := LoadRouteNames("test/iproute2/etc/rt_tables")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(idx.ByNumber(100).Name).To(Equal("custom"))
+			Expect(idx.ByName("custom").Comment).To(Equal("my table"))
+
+			_, err = LoadRouteNames("test/iproute2/etc/rt_nothing")
+			Expect(err).To(MatchError(fs.ErrNotExist))
+		})
+
+		It("prefers etc over usr, merging drop-ins", func() {
+			idx, err := LoadIPRoute2("rt_tables")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(slices.Collect(idx.All())).To(HaveExactElements(
+				HaveField("Name", "local"),
+				HaveField("Name", "main"),
+				HaveField("Name", "default"),
+				HaveField("Name", "custom"),
+				HaveField("Name", "vrf-red"),
+				HaveField("Name", "etc-shadowed"),
+				HaveField("Name", "zz"),
+				HaveField("Name", "primary"),
+			))
+			Expect(idx.ByNumber(254).Name).To(Equal("primary"))
+			Expect(idx.ByName("main").Number).To(Equal(uint32(254)))
+			Expect(idx.ByName("usr-shadowed")).To(BeNil())
+			Expect(idx.ByName("ignored")).To(BeNil())
+		})
+
+		It("falls back to usr", func() {
+			idx, err := LoadIPRoute2("rt_protos")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(idx.ByName("bgp").Number).To(Equal(uint32(186)))
+		})
+
+		It("reports missing tables", func() {
+			_, err := LoadIPRoute2("rt_scopes")
+			Expect(err).To(MatchError(fs.ErrNotExist))
+		})
+
+		It("reports parse errors", func() {
+			_, err := LoadIPRoute2("rt_realms")
+			Expect(err).To(MatchError(ContainSubstring("test/iproute2/etc/rt_realms:1: invalid number")))
+
+			idx, err := LoadIPRoute2WithOptions("rt_realms", ParseOptions{Mode: ParseCollectAll})
+			var perrs ParseErrors
+			Expect(errors.As(err, &perrs)).To(BeTrue())
+			Expect(perrs).To(HaveExactElements(
+				MatchError(ContainSubstring("test/iproute2/etc/rt_realms:1: invalid number")),
+				MatchError(ContainSubstring("test/iproute2/etc/rt_realms.d/bad.conf:2: invalid number")),
+			))
+			Expect(slices.Collect(idx.All())).To(HaveExactElements(HaveField("Name", "cosmos")))
+		})
+
+	})
+
+	Context("index", func() {
+
+		It("merges", func() {
+			idx := NewRouteNameIndex(BuiltinRouteTables)
+			idx.MergeIndex(NewRouteNameIndex([]RouteName{{Name: "primary", Number: 254}}))
+			Expect(idx.ByNumber(254).Name).To(Equal("primary"))
+			Expect(idx.ByName("main").Number).To(Equal(uint32(254)))
+			Expect(idx.AllByNumber(254)).To(HaveLen(2))
+			Expect(idx.AllByName("main")).To(HaveLen(1))
+		})
+
+	})
+
+	Context("package-level lookups", func() {
+
+		BeforeEach(func() {
+			reset := func() {
+				RouteProtocols = RouteNameIndex{}
+				RouteTables = RouteNameIndex{}
+				RouteScopes = RouteNameIndex{}
+				RouteRealms = RouteNameIndex{}
+				RouteDSFields = RouteNameIndex{}
+			}
+			reset()
+			DeferCleanup(reset)
+		})
+
+		It("looks up builtin names", func() {
+			Expect(RouteProtocolByNumber(2).Name).To(Equal("kernel"))
+			Expect(RouteProtocolByName("bgp").Number).To(Equal(uint32(186)))
+			Expect(RouteTableByNumber(254).Name).To(Equal("main"))
+			Expect(RouteTableByName("local").Number).To(Equal(uint32(255)))
+			Expect(RouteScopeByNumber(253).Name).To(Equal("link"))
+			Expect(RouteScopeByName("host").Number).To(Equal(uint32(254)))
+			Expect(RouteRealmByNumber(0).Name).To(Equal("cosmos"))
+			Expect(RouteRealmByName("cosmos")).NotTo(BeNil())
+			Expect(RouteDSFieldByNumber(0xb8).Name).To(Equal("EF"))
+			Expect(RouteDSFieldByName("AF11").Number).To(Equal(uint32(0x28)))
+		})
+
+		It("replaces indices", func() {
+			idx := NewRouteNameIndex([]RouteName{{Name: "foo", Number: 42}})
+			SetRouteProtocols(idx)
+			SetRouteTables(idx)
+			SetRouteScopes(idx)
+			SetRouteRealms(idx)
+			SetRouteDSFields(idx)
+			Expect(RouteProtocolByNumber(42).Name).To(Equal("foo"))
+			Expect(RouteTableByName("foo")).NotTo(BeNil())
+			Expect(RouteScopeByName("foo")).NotTo(BeNil())
+			Expect(RouteRealmByName("foo")).NotTo(BeNil())
+			Expect(RouteDSFieldByName("foo")).NotTo(BeNil())
+			Expect(RouteProtocolByName("kernel")).To(BeNil())
+		})
+
+	})
+
+})
@@ -0,0 +1,137 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ipv6ExtensionHeaders lists the next header values that the IANA Assigned
+// Internet Protocol Numbers registry flags as IPv6 extension headers, see
+// also https://www.iana.org/assignments/protocol-numbers and RFC 7045.
+var ipv6ExtensionHeaders = [256]bool{
+	0:   true, // IPv6 Hop-by-Hop Option [RFC8200]
+	43:  true, // Routing Header for IPv6 [RFC8200]
+	44:  true, // Fragment Header for IPv6 [RFC8200]
+	50:  true, // Encapsulating Security Payload [RFC4303]
+	51:  true, // Authentication Header [RFC4302]
+	60:  true, // Destination Options for IPv6 [RFC8200]
+	135: true, // Mobility Header [RFC6275]
+	139: true, // Host Identity Protocol [RFC7401]
+	140: true, // Shim6 Protocol [RFC5533]
+	253: true, // Use for experimentation and testing [RFC3692][RFC4727]
+	254: true, // Use for experimentation and testing [RFC3692][RFC4727]
+}
+
+// IsIPv6ExtensionHeader returns true if the specified next header value in an
+// IPv6 packet denotes an IPv6 extension header, as opposed to an upper-layer
+// protocol.
+func IsIPv6ExtensionHeader(number uint8) bool {
+	return ipv6ExtensionHeaders[number]
+}
+
+// IPv6 next header values requiring special treatment when walking a chain.
+const (
+	ipv6FragmentHeader = 44
+	ipv6ESPHeader      = 50
+	ipv6AuthHeader     = 51
+	ipv6NoNextHeader   = 59
+)
+
+// IPv6Header describes a single header in the next-header chain of an IPv6
+// packet, starting with the first header following the fixed IPv6 header.
+type IPv6Header struct {
+	Number   uint8     // Next header value identifying this header.
+	Protocol *Protocol // Protocol details, or nil if unknown.
+	Offset   int       // Offset of this header from the start of the packet.
+	Length   int       // Length of this header, or of the remaining payload.
+}
+
+// ExtensionHeader returns true if this header is an IPv6 extension header,
+// and false if it is the upper-layer payload.
+func (h IPv6Header) ExtensionHeader() bool {
+	return IsIPv6ExtensionHeader(h.Number)
+}
+
+// IPv6HeaderChain walks the next-header chain of the specified raw IPv6
+// packet, beginning with the fixed IPv6 header, and returns the headers
+// following the fixed header in order, naming them using this index.
+//
+// The walk ends with the first upper-layer header, which then spans the
+// remaining packet, or with "No Next Header". As their payload is opaque, the
+// walk also ends after an ESP header as well as after a fragment header of a
+// non-first fragment.
+//
+// If the packet is truncated or malformed, IPv6HeaderChain returns the headers
+// walked so far together with an error.
+func (i *ProtocolIndex) IPv6HeaderChain(packet []byte) ([]IPv6Header, error) {
+	if len(packet) < 40 {
+		return nil, errors.New("truncated IPv6 header")
+	}
+	if packet[0]>>4 != 6 {
+		return nil, errors.New("not an IPv6 packet")
+	}
+	var headers []IPv6Header
+	next := packet[6]
+	offset := 40
+	for {
+		header := IPv6Header{
+			Number:   next,
+			Protocol: i.ByNumber(next),
+			Offset:   offset,
+		}
+		remaining := len(packet) - offset
+		switch {
+		case next == ipv6NoNextHeader:
+			return append(headers, header), nil
+		case next == ipv6ESPHeader, !IsIPv6ExtensionHeader(next):
+			header.Length = remaining
+			return append(headers, header), nil
+		}
+		if remaining < 8 {
+			return headers, errors.New("truncated IPv6 extension header")
+		}
+		var fragmentOffset uint16
+		switch next {
+		case ipv6FragmentHeader:
+			header.Length = 8
+			fragmentOffset = binary.BigEndian.Uint16(packet[offset+2:]) >> 3
+		case ipv6AuthHeader:
+			header.Length = (int(packet[offset+1]) + 2) * 4
+		default:
+			header.Length = (int(packet[offset+1]) + 1) * 8
+		}
+		if header.Length > remaining {
+			return headers, errors.New("truncated IPv6 extension header")
+		}
+		headers = append(headers, header)
+		if fragmentOffset != 0 {
+			return headers, nil
+		}
+		next = packet[offset]
+		offset += header.Length
+	}
+}
+
+// IPv6HeaderChain walks the next-header chain of the specified raw IPv6
+// packet, beginning with the fixed IPv6 header, and returns the headers
+// following the fixed header in order, naming them using the protocols index.
+// See also [ProtocolIndex.IPv6HeaderChain].
+func IPv6HeaderChain(packet []byte) ([]IPv6Header, error) {
+	idx := defaultProtocols.rlock()
+	defer defaultProtocols.runlock()
+	return idx.IPv6HeaderChain(packet)
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// ipv6Packet returns a raw IPv6 packet with the specified first next header
+// value, followed by the specified headers and payload.
+func ipv6Packet(next uint8, rest ...byte) []byte {
+	packet := make([]byte, 40, 40+len(rest))
+	packet[0] = 0x60
+	packet[6] = next
+	return append(packet, rest...)
+}
+
+var _ = Describe("IPv6 extension headers", func() {
+
+	BeforeEach(func() {
+		Protocols = ProtocolIndex{}
+		DeferCleanup(func() {
+			Protocols = ProtocolIndex{}
+		})
+	})
+
+	It("classifies protocol numbers", func() {
+		Expect(IsIPv6ExtensionHeader(0)).To(BeTrue())
+		Expect(IsIPv6ExtensionHeader(44)).To(BeTrue())
+		Expect(IsIPv6ExtensionHeader(254)).To(BeTrue())
+		Expect(IsIPv6ExtensionHeader(6)).To(BeFalse())
+		Expect(IsIPv6ExtensionHeader(59)).To(BeFalse())
+		Expect(IsIPv6ExtensionHeader(ProtocolByName("ipv6-opts").Number)).To(BeTrue())
+		Expect(IsIPv6ExtensionHeader(ProtocolByName("ah").Number)).To(BeTrue())
+		Expect(IsIPv6ExtensionHeader(ProtocolByName("udp").Number)).To(BeFalse())
+	})
+
+	It("rejects non-IPv6 and truncated packets", func() {
+		_, err := IPv6HeaderChain(make([]byte, 39))
+		Expect(err).To(MatchError("truncated IPv6 header"))
+		_, err = IPv6HeaderChain(make([]byte, 40))
+		Expect(err).To(MatchError("not an IPv6 packet"))
+	})
+
+	It("walks an upper-layer protocol only", func() {
+		headers, err := IPv6HeaderChain(ipv6Packet(17, make([]byte, 8)...))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(headers).To(HaveExactElements(IPv6Header{
+			Number: 17, Protocol: ProtocolByName("udp"), Offset: 40, Length: 8,
+		}))
+		Expect(headers[0].ExtensionHeader()).To(BeFalse())
+	})
+
+	It("walks a chain of extension headers", func() {
+		packet := ipv6Packet(0,
+			// Hop-by-Hop options, 8 octets
+			60, 0, 5, 2, 0, 0, 1, 0,
+			// Destination options, 16 octets
+			43, 1, 1, 4, 0, 0, 0, 0, 1, 4, 0, 0, 0, 0, 1, 0,
+			// Routing header, 8 octets
+			51, 0, 0, 0, 0, 0, 0, 0,
+			// Authentication header, 12 octets
+			44, 1, 0, 0, 0, 0, 0, 1, 0, 0, 0, 1,
+			// Fragment header, first fragment
+			58, 0, 0, 1, 0, 0, 0, 42,
+			// ICMPv6 echo request
+			128, 0, 0, 0, 0, 0, 0, 0)
+		headers, err := IPv6HeaderChain(packet)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(headers).To(HaveExactElements(
+			IPv6Header{Number: 0, Protocol: ProtocolByNumber(0), Offset: 40, Length: 8},
+			IPv6Header{Number: 60, Protocol: ProtocolByName("ipv6-opts"), Offset: 48, Length: 16},
+			IPv6Header{Number: 43, Protocol: ProtocolByName("ipv6-route"), Offset: 64, Length: 8},
+			IPv6Header{Number: 51, Protocol: ProtocolByName("ah"), Offset: 72, Length: 12},
+			IPv6Header{Number: 44, Protocol: ProtocolByName("ipv6-frag"), Offset: 84, Length: 8},
+			IPv6Header{Number: 58, Protocol: ProtocolByName("ipv6-icmp"), Offset: 92, Length: 8},
+		))
+		Expect(headers[0].ExtensionHeader()).To(BeTrue())
+	})
+
+	It("stops at opaque payloads", func() {
+		headers, err := IPv6HeaderChain(ipv6Packet(59, 1, 2, 3))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(headers).To(HaveExactElements(
+			HaveField("Length", 0)))
+
+		headers, err = IPv6HeaderChain(ipv6Packet(50, make([]byte, 20)...))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(headers).To(HaveExactElements(
+			IPv6Header{Number: 50, Protocol: ProtocolByName("esp"), Offset: 40, Length: 20}))
+
+		headers, err = IPv6HeaderChain(ipv6Packet(44,
+			6, 0, 0x05, 0x00, 0, 0, 0, 42,
+			1, 2, 3, 4))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(headers).To(HaveExactElements(
+			IPv6Header{Number: 44, Protocol: ProtocolByName("ipv6-frag"), Offset: 40, Length: 8}))
+	})
+
+	It("reports truncated extension headers", func() {
+		headers, err := IPv6HeaderChain(ipv6Packet(0, 60, 0, 0, 0))
+		Expect(err).To(MatchError("truncated IPv6 extension header"))
+		Expect(headers).To(BeEmpty())
+
+		headers, err = IPv6HeaderChain(ipv6Packet(0,
+			60, 0, 0, 0, 0, 0, 0, 0,
+			6, 1, 0, 0, 0, 0, 0, 0))
+		Expect(err).To(MatchError("truncated IPv6 extension header"))
+		Expect(headers).To(HaveExactElements(HaveField("Number", uint8(0))))
+	})
+
+	It("names headers using a specific index", func() {
+		idx := NewProtocolIndex([]Protocol{{Name: "foo", Number: 17}})
+		headers, err := idx.IPv6HeaderChain(ipv6Packet(17))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(headers).To(HaveExactElements(
+			HaveField("Protocol.Name", "foo")))
+		headers, err = idx.IPv6HeaderChain(ipv6Packet(6))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(headers).To(HaveExactElements(
+			HaveField("Protocol", BeNil())))
+	})
+
+})
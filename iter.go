@@ -0,0 +1,35 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import "iter"
+
+// allOf returns an iterator over the specified entries in their order, yielding
+// each entry only once: the same entry might have been merged multiple times
+// into an index, such as when merging overlapping indices.
+func allOf[E any](entries []*E) iter.Seq[*E] {
+	return func(yield func(*E) bool) {
+		seen := make(map[*E]struct{}, len(entries))
+		for _, entry := range entries {
+			if _, ok := seen[entry]; ok {
+				continue
+			}
+			seen[entry] = struct{}{}
+			if !yield(entry) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import "sync"
+
+// lazyIndex guards a package-level index variable, such as Services, against
+// concurrent access, initializing it from its builtin definitions upon first
+// use.
+type lazyIndex[I any] struct {
+	mu      sync.RWMutex
+	index   *I            // the guarded package-level index variable.
+	isZero  func(*I) bool // reports whether the index still needs initialization.
+	builtin func() I      // returns a new index with the builtin definitions.
+}
+
+// rlock read-locks the index, initializing it first if necessary, and then
+// returns it. Callers must call runlock when done with the index.
+func (l *lazyIndex[I]) rlock() *I {
+	for {
+		l.mu.RLock()
+		if !l.isZero(l.index) {
+			return l.index
+		}
+		l.mu.RUnlock()
+		l.mu.Lock()
+		if l.isZero(l.index) {
+			*l.index = l.builtin()
+		}
+		l.mu.Unlock()
+	}
+}
+
+// runlock releases the read lock taken by rlock.
+func (l *lazyIndex[I]) runlock() {
+	l.mu.RUnlock()
+}
+
+// set atomically replaces the index with the specified one; lookups in flight
+// finish on the old index.
+func (l *lazyIndex[I]) set(index I) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	*l.index = index
+}
+
+var defaultServices = lazyIndex[ServiceIndex]{
+	index:   &Services,
+	isZero:  func(i *ServiceIndex) bool { return i.Names == nil },
+	builtin: func() ServiceIndex { return NewServiceIndex(BuiltinServices) },
+}
+
+var defaultProtocols = lazyIndex[ProtocolIndex]{
+	index:   &Protocols,
+	isZero:  func(i *ProtocolIndex) bool { return i.Numbers == nil },
+	builtin: func() ProtocolIndex { return NewProtocolIndex(BuiltinProtocols) },
+}
+
+var defaultEtherTypes = lazyIndex[EtherTypeIndex]{
+	index:   &EtherTypes,
+	isZero:  func(i *EtherTypeIndex) bool { return i.Numbers == nil },
+	builtin: func() EtherTypeIndex { return NewEtherTypeIndex(BuiltinEtherTypes) },
+}
+
+var defaultRPCPrograms = lazyIndex[RPCProgramIndex]{
+	index:   &RPCPrograms,
+	isZero:  func(i *RPCProgramIndex) bool { return i.Numbers == nil },
+	builtin: func() RPCProgramIndex { return NewRPCProgramIndex(BuiltinRPCPrograms) },
+}
+
+var defaultICMPv4Types = lazyIndex[ICMPIndex]{
+	index:   &ICMPv4Types,
+	isZero:  func(i *ICMPIndex) bool { return i.Numbers == nil },
+	builtin: func() ICMPIndex { return NewICMPIndex(builtinProtocolByNumber(1), BuiltinICMPv4Types) },
+}
+
+var defaultICMPv6Types = lazyIndex[ICMPIndex]{
+	index:   &ICMPv6Types,
+	isZero:  func(i *ICMPIndex) bool { return i.Numbers == nil },
+	builtin: func() ICMPIndex { return NewICMPIndex(builtinProtocolByNumber(58), BuiltinICMPv6Types) },
+}
+
+var defaultHardwareTypes = lazyIndex[HardwareTypeIndex]{
+	index:   &HardwareTypes,
+	isZero:  func(i *HardwareTypeIndex) bool { return i.Numbers == nil },
+	builtin: func() HardwareTypeIndex { return NewHardwareTypeIndex(BuiltinHardwareTypes) },
+}
+
+var defaultRouteProtocols = lazyIndex[RouteNameIndex]{
+	index:   &RouteProtocols,
+	isZero:  func(i *RouteNameIndex) bool { return i.Numbers == nil },
+	builtin: func() RouteNameIndex { return NewRouteNameIndex(BuiltinRouteProtocols) },
+}
+
+var defaultRouteTables = lazyIndex[RouteNameIndex]{
+	index:   &RouteTables,
+	isZero:  func(i *RouteNameIndex) bool { return i.Numbers == nil },
+	builtin: func() RouteNameIndex { return NewRouteNameIndex(BuiltinRouteTables) },
+}
+
+var defaultRouteScopes = lazyIndex[RouteNameIndex]{
+	index:   &RouteScopes,
+	isZero:  func(i *RouteNameIndex) bool { return i.Numbers == nil },
+	builtin: func() RouteNameIndex { return NewRouteNameIndex(BuiltinRouteScopes) },
+}
+
+var defaultRouteRealms = lazyIndex[RouteNameIndex]{
+	index:   &RouteRealms,
+	isZero:  func(i *RouteNameIndex) bool { return i.Numbers == nil },
+	builtin: func() RouteNameIndex { return NewRouteNameIndex(BuiltinRouteRealms) },
+}
+
+var defaultRouteDSFields = lazyIndex[RouteNameIndex]{
+	index:   &RouteDSFields,
+	isZero:  func(i *RouteNameIndex) bool { return i.Numbers == nil },
+	builtin: func() RouteNameIndex { return NewRouteNameIndex(BuiltinRouteDSFields) },
+}
+
+var defaultDSCPs = lazyIndex[CodepointIndex]{
+	index:   &DSCPs,
+	isZero:  func(i *CodepointIndex) bool { return i.Numbers == nil },
+	builtin: func() CodepointIndex { return NewCodepointIndex(BuiltinDSCPs) },
+}
+
+var defaultECNs = lazyIndex[CodepointIndex]{
+	index:   &ECNs,
+	isZero:  func(i *CodepointIndex) bool { return i.Numbers == nil },
+	builtin: func() CodepointIndex { return NewCodepointIndex(BuiltinECNs) },
+}
+
+var defaultAddressFamilies = lazyIndex[AddressFamilyIndex]{
+	index:   &AddressFamilies,
+	isZero:  func(i *AddressFamilyIndex) bool { return i.Numbers == nil },
+	builtin: func() AddressFamilyIndex { return NewAddressFamilyIndex(BuiltinAddressFamilies) },
+}
+
+var defaultIPOptions = lazyIndex[HeaderOptionIndex]{
+	index:   &IPOptions,
+	isZero:  func(i *HeaderOptionIndex) bool { return i.Numbers == nil },
+	builtin: func() HeaderOptionIndex { return NewHeaderOptionIndex(BuiltinIPOptions) },
+}
+
+var defaultIPv6Options = lazyIndex[HeaderOptionIndex]{
+	index:   &IPv6Options,
+	isZero:  func(i *HeaderOptionIndex) bool { return i.Numbers == nil },
+	builtin: func() HeaderOptionIndex { return NewHeaderOptionIndex(BuiltinIPv6Options) },
+}
+
+var defaultTCPOptions = lazyIndex[HeaderOptionIndex]{
+	index:   &TCPOptions,
+	isZero:  func(i *HeaderOptionIndex) bool { return i.Numbers == nil },
+	builtin: func() HeaderOptionIndex { return NewHeaderOptionIndex(BuiltinTCPOptions) },
+}
+
+var defaultOUIs = lazyIndex[OUIIndex]{
+	index:   &OUIs,
+	isZero:  func(i *OUIIndex) bool { return i.Prefixes == nil },
+	builtin: builtinOUIs,
+}
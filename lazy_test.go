@@ -0,0 +1,88 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("package-level indices", func() {
+
+	BeforeEach(func() {
+		Services = ServiceIndex{}
+		Protocols = ProtocolIndex{}
+		EtherTypes = EtherTypeIndex{}
+		DeferCleanup(func() {
+			Services = ServiceIndex{}
+			Protocols = ProtocolIndex{}
+			EtherTypes = EtherTypeIndex{}
+		})
+	})
+
+	It("initializes concurrently without races", func() {
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				Expect(ServiceByName("domain", "udp")).NotTo(BeNil())
+				Expect(ProtocolByNumber(6)).NotTo(BeNil())
+				Expect(EtherTypeByNumber(0x0800)).NotTo(BeNil())
+			}()
+		}
+		wg.Wait()
+	})
+
+	It("replaces indices while lookups are in flight", func() {
+		p, err := ParseProtocols(strings.NewReader("foobar 12\n"))
+		Expect(err).NotTo(HaveOccurred())
+		protos := NewProtocolIndex(p)
+		s, err := ParseServices(strings.NewReader("crash 666/foobar\n"), protos)
+		Expect(err).NotTo(HaveOccurred())
+		e, err := ParseEtherTypes(strings.NewReader("test 9000\n"))
+		Expect(err).NotTo(HaveOccurred())
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 100 {
+					_ = ServiceByPort(53, "")
+					_ = ProtocolByName("tcp")
+					_ = EtherTypeByName("IPv4")
+				}
+			}()
+		}
+		SetServices(NewServiceIndex(s))
+		SetProtocols(protos)
+		SetEtherTypes(NewEtherTypeIndex(e))
+		wg.Wait()
+
+		Expect(ServiceByPort(666, "")).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal("crash"),
+		})))
+		Expect(ServiceByPort(53, "")).To(BeNil())
+		Expect(ProtocolByName("foobar")).NotTo(BeNil())
+		Expect(EtherTypeByNumber(0x9000)).NotTo(BeNil())
+	})
+
+})
@@ -0,0 +1,274 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"iter"
+	"net/netip"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Network describes a network by its official name and its network address
+// prefix, with optional alias names and comment, as found in networks(5).
+//
+// On purpose, we don't stick with the stuttering POSIX C library names, but
+// instead aim for more Go-like type names. After all, Go isn't similar to C,
+// except for using letters, signs, and braces.
+type Network struct {
+	Name    string       // Official network name.
+	Prefix  netip.Prefix // Network address prefix.
+	Aliases []string     // List of aliases.
+	Comment string       // Entry comment, if present.
+}
+
+// NetworkIndex indexes the known networks by either name (native as well as
+// aliases) and by network address prefix.
+//
+// When multiple networks share the same name or prefix, the Precedence rule
+// decides which network becomes the primary one. The Precedence rule needs to
+// be set before merging any networks.
+type NetworkIndex struct {
+	Names      map[string]*Network       // Index by network name, including aliases.
+	Prefixes   map[netip.Prefix]*Network // Index by (masked) network prefix.
+	Precedence Precedence                // Rule for picking the primary network.
+
+	entries []*Network // all merged networks in definition order.
+}
+
+// NewNetworkIndex returns a NetworkIndex object initialized with the specified
+// networks.
+func NewNetworkIndex(networks []Network) NetworkIndex {
+	i := NetworkIndex{
+		Names:    map[string]*Network{},
+		Prefixes: map[netip.Prefix]*Network{},
+	}
+	i.Merge(networks)
+	return i
+}
+
+// LoadNetworks returns a NetworkIndex object initialized from the definitions
+// in the named file, such as "/etc/networks".
+func LoadNetworks(name string) (NetworkIndex, error) {
+	return LoadNetworksWithOptions(name, ParseOptions{})
+}
+
+// LoadNetworksWithOptions returns a NetworkIndex object initialized from the
+// definitions in the named file, parsing it as specified by the options. If
+// the options don't specify a file name, then the specified name is used. In
+// ParseCollectAll mode, the returned index contains all well-formed
+// definitions, even when a ParseErrors error is returned.
+func LoadNetworksWithOptions(name string, opts ParseOptions) (NetworkIndex, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return NewNetworkIndex(nil), err
+	}
+	defer f.Close()
+	if opts.Filename == "" {
+		opts.Filename = name
+	}
+	networks, err := ParseNetworksWithOptions(f, opts)
+	if networks == nil {
+		return NewNetworkIndex(nil), err
+	}
+	return NewNetworkIndex(networks), err
+}
+
+// Merge a list of Network descriptions into the current Networks index,
+// potentially overriding existing entries in the index in case of duplicates,
+// depending on the index's Precedence rule.
+func (i *NetworkIndex) Merge(networks []Network) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for idx, network := range networks {
+		setPrimary(i.Names, network.Name, &networks[idx], firstWins)
+		for _, alias := range network.Aliases {
+			setPrimary(i.Names, alias, &networks[idx], firstWins)
+		}
+		setPrimary(i.Prefixes, network.Prefix.Masked(), &networks[idx], firstWins)
+		i.entries = append(i.entries, &networks[idx])
+	}
+}
+
+// MergeIndex merges another NetworkIndex into the current index, potentially
+// overriding existing entries in case of duplicates, depending on the index's
+// Precedence rule.
+func (i *NetworkIndex) MergeIndex(ni NetworkIndex) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for name, network := range ni.Names {
+		setPrimary(i.Names, name, network, firstWins)
+	}
+	for prefix, network := range ni.Prefixes {
+		setPrimary(i.Prefixes, prefix, network, firstWins)
+	}
+	i.entries = append(i.entries, ni.entries...)
+}
+
+// init initializes the index maps, if not already done.
+func (i *NetworkIndex) init() {
+	if i.Names == nil {
+		i.Names = map[string]*Network{}
+	}
+	if i.Prefixes == nil {
+		i.Prefixes = map[netip.Prefix]*Network{}
+	}
+}
+
+// ByName returns the Network for the specified (alias) name, or nil if not
+// found.
+func (i *NetworkIndex) ByName(name string) *Network {
+	return i.Names[name]
+}
+
+// ByPrefix returns the Network for exactly the specified network prefix, or
+// nil if not found. Any host bits in the specified prefix are ignored.
+func (i *NetworkIndex) ByPrefix(prefix netip.Prefix) *Network {
+	return i.Prefixes[prefix.Masked()]
+}
+
+// ByAddr returns the Network with the longest prefix containing the specified
+// address, or nil if no network contains the address. IPv4-mapped IPv6
+// addresses match IPv4 networks.
+func (i *NetworkIndex) ByAddr(addr netip.Addr) *Network {
+	if addr.Is4In6() {
+		addr = addr.Unmap()
+	}
+	addr = addr.WithZone("")
+	for bits := addr.BitLen(); bits >= 0; bits-- {
+		prefix, err := addr.Prefix(bits)
+		if err != nil {
+			return nil
+		}
+		if network, ok := i.Prefixes[prefix]; ok {
+			return network
+		}
+	}
+	return nil
+}
+
+// AllByName returns all networks with the specified (alias) name in the order
+// of their original definitions, including networks overridden by later
+// merges.
+func (i *NetworkIndex) AllByName(name string) []*Network {
+	var networks []*Network
+	for network := range i.All() {
+		if network.Name == name || slices.Contains(network.Aliases, name) {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+// All returns an iterator over all networks merged into this index, in the
+// order of their original definitions. Each network is yielded only once,
+// regardless of its aliases, and including networks that have been overridden
+// by later merges.
+func (i *NetworkIndex) All() iter.Seq[*Network] {
+	return allOf(i.entries)
+}
+
+// ParseNetworks parses network definitions from the given Reader and returns
+// them as a list of Network(s). Incomplete definitions are silently skipped,
+// while invalid network numbers result in an error.
+func ParseNetworks(r io.Reader) ([]Network, error) {
+	return ParseNetworksWithOptions(r, ParseOptions{})
+}
+
+// ParseNetworksWithOptions parses network definitions from the given Reader
+// as specified by the options and returns them as a list of Network(s).
+//
+// Network numbers are in dotted decimal notation, optionally leaving out
+// trailing octets, such as "10", "172.16", and "192.168.1". In this case, the
+// prefix length covers just the specified octets. For complete dotted quads,
+// the prefix length is inferred from the trailing zero octets, so "127.0.0.0"
+// becomes 127.0.0.0/8. Additionally, network numbers may be given in CIDR
+// notation, such as "10.0.0.0/8" or "fd00::/8".
+func ParseNetworksWithOptions(r io.Reader, opts ParseOptions) ([]Network, error) {
+	networks := []Network{}
+	lp := lineParser{opts: opts}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lp.next()
+		line := strings.TrimSpace(scanner.Text())
+		definition, comment := splitComment(line)
+		fields := strings.Fields(definition)
+		if len(fields) == 0 {
+			continue // skip empty lines and comment lines.
+		}
+		if len(fields) < 2 {
+			if err := lp.malformed(line, "missing network number", false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		prefix, err := parseNetworkNumber(fields[1])
+		if err != nil {
+			if err := lp.malformed(line, "invalid network number", true); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		networks = append(networks, Network{
+			Name:    fields[0],
+			Prefix:  prefix,
+			Aliases: fields[2:],
+			Comment: comment,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return networks, lp.err()
+}
+
+// parseNetworkNumber parses a network number either in CIDR notation or in
+// the networks(5) dotted decimal notation with optional trailing octets left
+// out.
+func parseNetworkNumber(number string) (netip.Prefix, error) {
+	if strings.Contains(number, "/") {
+		prefix, err := netip.ParsePrefix(number)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+	octets := strings.Split(number, ".")
+	if len(octets) > 4 {
+		return netip.Prefix{}, errors.New("too many octets")
+	}
+	var addr [4]byte
+	for idx, octet := range octets {
+		value, err := strconv.ParseUint(octet, 10, 8)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		addr[idx] = byte(value)
+	}
+	bits := len(octets) * 8
+	if len(octets) == 4 {
+		for bits > 0 && addr[bits/8-1] == 0 {
+			bits -= 8
+		}
+	}
+	return netip.PrefixFrom(netip.AddrFrom4(addr), bits), nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"net/netip"
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("networks", func() {
+
+	Context("parsing descriptions", func() {
+
+		It("returns correct descriptions", func() {
+			n, err := ParseNetworks(strings.NewReader(`
+# A comment
+loopback	127.0.0.0	lo-net # the loopback network
+private		10
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(HaveExactElements(
+				MatchFields(IgnoreExtras, Fields{
+					"Name":    Equal("loopback"),
+					"Prefix":  Equal(netip.MustParsePrefix("127.0.0.0/8")),
+					"Aliases": ConsistOf("lo-net"),
+					"Comment": Equal("the loopback network"),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Name":    Equal("private"),
+					"Prefix":  Equal(netip.MustParsePrefix("10.0.0.0/8")),
+					"Aliases": BeEmpty(),
+					"Comment": BeEmpty(),
+				}),
+			))
+		})
+
+		DescribeTable("network numbers",
+			func(number string, expected string) {
+				n, err := ParseNetworks(strings.NewReader("net " + number))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(n).To(HaveLen(1))
+				Expect(n[0].Prefix).To(Equal(netip.MustParsePrefix(expected)))
+			},
+			Entry(nil, "0.0.0.0", "0.0.0.0/0"),
+			Entry(nil, "169.254.0.0", "169.254.0.0/16"),
+			Entry(nil, "192.168.1.0", "192.168.1.0/24"),
+			Entry(nil, "192.168.1.1", "192.168.1.1/32"),
+			Entry(nil, "10.0", "10.0.0.0/16"),
+			Entry(nil, "192.168.1", "192.168.1.0/24"),
+			Entry(nil, "10.1.2.3/8", "10.0.0.0/8"),
+			Entry(nil, "fd00::/8", "fd00::/8"),
+		)
+
+		It("skips incomplete definitions", func() {
+			n, err := ParseNetworks(strings.NewReader("foo\nbar 10\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(n).To(HaveExactElements(HaveField("Name", "bar")))
+		})
+
+		DescribeTable("rejecting invalid network numbers",
+			func(number string) {
+				_, err := ParseNetworks(strings.NewReader("net " + number))
+				Expect(err).To(MatchError(ContainSubstring("invalid network number")))
+			},
+			Entry(nil, "256"),
+			Entry(nil, "1.2.3.4.5"),
+			Entry(nil, "foo"),
+			Entry(nil, "10.0.0.0/33"),
+		)
+
+		It("collects all errors", func() {
+			n, err := ParseNetworksWithOptions(strings.NewReader("foo\nbar 666\nbaz 10\n"),
+				ParseOptions{Mode: ParseCollectAll})
+			Expect(err).To(HaveLen(2))
+			Expect(n).To(HaveExactElements(HaveField("Name", "baz")))
+		})
+
+	})
+
+	Context("loading", func() {
+
+		It("reports an error for a non-existing file", func() {
+			_, err := LoadNetworks("test/non-existing-networks")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("loads and indexes", func() {
+			idx, err := LoadNetworks("test/networks")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(slices.Collect(idx.All())).To(HaveLen(7))
+			Expect(idx.ByName("lan").Name).To(Equal("my-lan"))
+			Expect(idx.ByName("private-172").Prefix).To(Equal(netip.MustParsePrefix("172.16.0.0/16")))
+			Expect(idx.ByName("link-local").Comment).To(Equal("RFC 3927"))
+			Expect(idx.ByName("foo")).To(BeNil())
+			Expect(idx.ByPrefix(netip.MustParsePrefix("127.0.0.1/8")).Name).To(Equal("loopback"))
+			Expect(idx.ByPrefix(netip.MustParsePrefix("127.0.0.0/16"))).To(BeNil())
+		})
+
+	})
+
+	Context("index", func() {
+
+		var idx NetworkIndex
+
+		BeforeEach(func() {
+			var err error
+			idx, err = LoadNetworks("test/networks")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		DescribeTable("longest prefix matching",
+			func(addr string, name string) {
+				n := idx.ByAddr(netip.MustParseAddr(addr))
+				if name == "" {
+					Expect(n).To(BeNil())
+					return
+				}
+				Expect(n).NotTo(BeNil())
+				Expect(n.Name).To(Equal(name))
+			},
+			Entry(nil, "127.0.0.1", "loopback"),
+			Entry(nil, "192.168.1.42", "my-lan"),
+			Entry(nil, "192.168.2.42", "default"),
+			Entry(nil, "10.11.12.13", "private-10"),
+			Entry(nil, "::ffff:10.11.12.13", "private-10"),
+			Entry(nil, "fd12::1%eth0", "ula"),
+			Entry(nil, "2001:db8::1", ""),
+		)
+
+		It("doesn't match invalid addresses", func() {
+			Expect(idx.ByAddr(netip.Addr{})).To(BeNil())
+		})
+
+		It("merges", func() {
+			other := NewNetworkIndex([]Network{
+				{Name: "loopback", Prefix: netip.MustParsePrefix("127.0.0.0/16")},
+				{Name: "host", Prefix: netip.MustParsePrefix("127.0.0.1/32")},
+			})
+			idx.MergeIndex(other)
+			Expect(idx.ByAddr(netip.MustParseAddr("127.0.0.1")).Name).To(Equal("host"))
+			Expect(idx.ByAddr(netip.MustParseAddr("127.0.1.1")).Name).To(Equal("loopback"))
+			Expect(idx.ByName("loopback").Prefix.Bits()).To(Equal(16))
+			Expect(idx.AllByName("loopback")).To(HaveLen(2))
+
+			var zero NetworkIndex
+			zero.Merge([]Network{{Name: "foo", Prefix: netip.MustParsePrefix("10.0.0.0/8")}})
+			Expect(zero.ByName("foo")).NotTo(BeNil())
+		})
+
+	})
+
+})
@@ -0,0 +1,140 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LoadNmapServices returns a ServiceIndex object initialized from the
+// definitions in the named file in nmap-services format.
+func LoadNmapServices(name string, protos ProtocolIndex) (ServiceIndex, error) {
+	return LoadNmapServicesWithOptions(name, protos, ParseOptions{})
+}
+
+// LoadNmapServicesWithOptions returns a ServiceIndex object initialized from
+// the definitions in the named file in nmap-services format, parsing it as
+// specified by the options. If the options don't specify a file name, then
+// the specified name is used. In ParseCollectAll mode, the returned index
+// contains all well-formed definitions, even when a ParseErrors error is
+// returned.
+func LoadNmapServicesWithOptions(name string, protos ProtocolIndex, opts ParseOptions) (ServiceIndex, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return NewServiceIndex(nil), err
+	}
+	defer f.Close()
+	if opts.Filename == "" {
+		opts.Filename = name
+	}
+	services, err := ParseNmapServicesWithOptions(f, protos, opts)
+	if services == nil {
+		return NewServiceIndex(nil), err
+	}
+	return NewServiceIndex(services), err
+}
+
+// ParseNmapServices parses network service definitions in nmap-services format
+// from the given Reader and returns them as a list of Service(s). Malformed
+// definitions as well as definitions with protocols not in the specified
+// ProtocolIndex are silently skipped.
+func ParseNmapServices(r io.Reader, p ProtocolIndex) ([]Service, error) {
+	return ParseNmapServicesWithOptions(r, p, ParseOptions{})
+}
+
+// ParseNmapServicesWithOptions parses network service definitions in
+// nmap-services format from the given Reader as specified by the options and
+// returns them as a list of Service(s).
+//
+// In contrast to services(5), each line defines a service by its name, its
+// port and protocol, and the frequency with which nmap found this port open,
+// followed by an optional comment:
+//
+//	http	80/tcp	0.484143	# World Wide Web HTTP
+//
+// The frequency is stored in the Service's Frequency field, ready for ranking
+// services by likelihood using ServiceIndex.RankedByPort.
+func ParseNmapServicesWithOptions(r io.Reader, p ProtocolIndex, opts ParseOptions) ([]Service, error) {
+	services := []Service{}
+	lp := lineParser{opts: opts}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lp.next()
+		line := strings.TrimSpace(scanner.Text())
+		definition, comment := splitComment(line)
+		fields := strings.Fields(definition)
+		if len(fields) == 0 {
+			continue // skip empty lines and comment lines.
+		}
+		if len(fields) < 2 {
+			if err := lp.malformed(line, "missing port/protocol", false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if len(fields) < 3 {
+			if err := lp.malformed(line, "missing frequency", false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		port, lastport, protocol, err := parsePortProtocol(fields[1])
+		if err != nil {
+			if err := lp.malformed(line, err.Error(), false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		frequency, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil || math.IsNaN(frequency) || math.IsInf(frequency, 0) || frequency < 0 {
+			if err := lp.malformed(line, "invalid frequency", false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		proto, ok := p.Names[protocol]
+		if !ok && !opts.KeepUnknownProtocols {
+			if err := lp.malformed(line, "unknown protocol", false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		services = append(services, Service{
+			Name:         fields[0],
+			Port:         port,
+			LastPort:     lastport,
+			ProtocolName: protocol,
+			Protocol:     proto,
+			Aliases:      []string{},
+			Comment:      comment,
+			Frequency:    frequency,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return services, lp.err()
+}
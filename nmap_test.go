@@ -0,0 +1,152 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("nmap services", func() {
+
+	var protos ProtocolIndex
+
+	BeforeEach(func() {
+		protos = NewProtocolIndex(BuiltinProtocols)
+	})
+
+	Context("parsing descriptions", func() {
+
+		It("returns correct descriptions", func() {
+			s, err := ParseNmapServices(strings.NewReader(`
+# A comment
+http	80/tcp	0.484143	# World Wide Web HTTP
+x11	6000-6063/tcp	0.001
+`), protos)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(HaveExactElements(
+				MatchFields(IgnoreExtras, Fields{
+					"Name":         Equal("http"),
+					"Port":         Equal(80),
+					"ProtocolName": Equal("tcp"),
+					"Protocol":     PointTo(HaveField("Number", uint8(6))),
+					"Aliases":      BeEmpty(),
+					"Comment":      Equal("World Wide Web HTTP"),
+					"Frequency":    Equal(0.484143),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Name":      Equal("x11"),
+					"Port":      Equal(6000),
+					"LastPort":  Equal(6063),
+					"Comment":   BeEmpty(),
+					"Frequency": Equal(0.001),
+				}),
+			))
+		})
+
+		It("silently skips malformed definitions", func() {
+			s, err := ParseNmapServices(strings.NewReader(`
+foo
+foo 80/tcp
+foo 80 0.1
+foo 99999/tcp 0.1
+foo 80/tcp bar
+foo 80/tcp NaN
+foo 80/tcp -1
+foo 80/abc 0.1
+http 80/tcp 0.484143
+`), protos)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(HaveExactElements(HaveField("Name", "http")))
+		})
+
+		It("collects all errors", func() {
+			s, err := ParseNmapServicesWithOptions(strings.NewReader("foo 80/tcp\nfoo 80/tcp bar\nhttp 80/tcp 0.5\n"),
+				protos, ParseOptions{Mode: ParseCollectAll})
+			Expect(err).To(HaveLen(2))
+			Expect(err.Error()).To(And(
+				ContainSubstring("missing frequency"),
+				ContainSubstring("invalid frequency")))
+			Expect(s).To(HaveExactElements(HaveField("Name", "http")))
+		})
+
+		It("keeps unknown protocols", func() {
+			s, err := ParseNmapServicesWithOptions(strings.NewReader("foo 80/abc 0.1\n"),
+				protos, ParseOptions{KeepUnknownProtocols: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(HaveExactElements(And(
+				HaveField("ProtocolName", "abc"),
+				HaveField("Protocol", BeNil()))))
+		})
+
+	})
+
+	Context("loading and ranking", func() {
+
+		It("reports an error for a non-existing file", func() {
+			_, err := LoadNmapServices("test/non-existing-nmap-services", protos)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("ranks services by frequency", func() {
+			idx, err := LoadNmapServices("test/nmap-services", protos)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(slices.Collect(idx.All())).To(HaveLen(6))
+			Expect(idx.RankedByPort(8080, "tcp")).To(HaveExactElements(
+				HaveField("Name", "http-proxy"),
+				HaveField("Name", "http-alt"),
+			))
+			Expect(idx.RankedByPort(80, "")).To(HaveExactElements(
+				HaveField("ProtocolName", "tcp"),
+				HaveField("ProtocolName", "udp"),
+				HaveField("ProtocolName", "sctp"),
+			))
+			Expect(idx.RankedByPort(443, "tcp")).To(BeEmpty())
+		})
+
+		It("keeps the definition order for equal frequencies", func() {
+			idx := NewServiceIndex([]Service{
+				{Name: "foo", Port: 42, ProtocolName: "tcp"},
+				{Name: "bar", Port: 42, ProtocolName: "tcp", Frequency: 0.1},
+				{Name: "baz", Port: 42, ProtocolName: "tcp"},
+			})
+			Expect(idx.RankedByPort(42, "tcp")).To(HaveExactElements(
+				HaveField("Name", "bar"),
+				HaveField("Name", "foo"),
+				HaveField("Name", "baz"),
+			))
+		})
+
+		It("ranks the package-level services", func() {
+			Services = ServiceIndex{}
+			DeferCleanup(func() {
+				Services = ServiceIndex{}
+			})
+			idx, err := LoadNmapServices("test/nmap-services", protos)
+			Expect(err).NotTo(HaveOccurred())
+			SetServices(idx)
+			Expect(RankedServicesByPort(8080, "")).To(HaveExactElements(
+				HaveField("Name", "http-proxy"),
+				HaveField("Name", "http-alt"),
+			))
+		})
+
+	})
+
+})
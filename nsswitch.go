@@ -0,0 +1,169 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// NSSwitch maps database names, such as "services" and "protocols", to their
+// lists of sources as configured in nsswitch.conf(5).
+//
+// Please note that nsswitch.conf(5) has no database for EtherTypes: its
+// "ethers" database maps MAC addresses to host names as in /etc/ethers. Thus,
+// EtherTypeChain(s) need to be set up explicitly.
+type NSSwitch map[string][]NSSwitchSource
+
+// NSSwitchSource is a source name, such as "files", together with its
+// criteria.
+type NSSwitchSource struct {
+	Name     string
+	Criteria Criteria
+}
+
+// defaultNSSwitchSources are used for databases not configured in
+// nsswitch.conf, as does glibc.
+var defaultNSSwitchSources = []NSSwitchSource{{Name: "files"}}
+
+// LoadNSSwitch returns the NSSwitch configuration read from the named file,
+// such as "/etc/nsswitch.conf".
+func LoadNSSwitch(name string) (NSSwitch, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseNSSwitch(f)
+}
+
+// ParseNSSwitch parses an nsswitch.conf(5) configuration from the given
+// Reader. Similar to glibc, malformed lines as well as malformed criteria are
+// silently skipped. As this package doesn't know about temporary failures, the
+// TRYAGAIN status is ignored; so are "merge" actions.
+func ParseNSSwitch(r io.Reader) (NSSwitch, error) {
+	nss := NSSwitch{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.SplitN(scanner.Text(), "#", 2)[0]) // There's always an element [0]
+		database, spec, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		database = strings.TrimSpace(database)
+		if database == "" {
+			continue
+		}
+		sources := []NSSwitchSource{}
+		for spec = strings.TrimSpace(spec); spec != ""; spec = strings.TrimSpace(spec) {
+			if strings.HasPrefix(spec, "[") {
+				criteria, rest, ok := strings.Cut(spec[1:], "]")
+				if !ok {
+					break
+				}
+				spec = rest
+				if len(sources) > 0 {
+					parseNSSwitchCriteria(criteria, &sources[len(sources)-1].Criteria)
+				}
+				continue
+			}
+			end := strings.IndexAny(spec, " \t[")
+			if end < 0 {
+				end = len(spec)
+			}
+			sources = append(sources, NSSwitchSource{Name: spec[:end]})
+			spec = spec[end:]
+		}
+		nss[database] = sources
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nss, nil
+}
+
+// parseNSSwitchCriteria parses the (space-separated) "STATUS=ACTION" items of a
+// criteria specification into the specified criteria.
+func parseNSSwitchCriteria(spec string, criteria *Criteria) {
+	for _, item := range strings.Fields(spec) {
+		status, act, ok := strings.Cut(item, "=")
+		if !ok {
+			continue
+		}
+		var action Action
+		switch strings.ToLower(act) {
+		case "return":
+			action = ActionReturn
+		case "continue":
+			action = ActionContinue
+		default:
+			continue
+		}
+		negate := strings.HasPrefix(status, "!")
+		status = strings.ToUpper(strings.TrimPrefix(status, "!"))
+		for _, s := range []struct {
+			status string
+			action *Action
+		}{
+			{"SUCCESS", &criteria.Success},
+			{"NOTFOUND", &criteria.NotFound},
+			{"UNAVAIL", &criteria.Unavail},
+		} {
+			if (s.status == status) != negate {
+				*s.action = action
+			}
+		}
+	}
+}
+
+// sourcesFor returns the configured sources for the named database, or the
+// default sources if the database isn't configured.
+func (ns NSSwitch) sourcesFor(database string) []NSSwitchSource {
+	if sources, ok := ns[database]; ok {
+		return sources
+	}
+	return defaultNSSwitchSources
+}
+
+// ServiceChain returns a ServiceChain for the "services" database, taking the
+// named sources from the specified map, such as "files" mapping to a
+// ServiceIndex loaded from /etc/services. Sources missing from the map are
+// considered to be unavailable.
+func (ns NSSwitch) ServiceChain(sources map[string]ServiceSource) ServiceChain {
+	return buildChain(ns.sourcesFor("services"), sources)
+}
+
+// ProtocolChain returns a ProtocolChain for the "protocols" database, taking
+// the named sources from the specified map. Sources missing from the map are
+// considered to be unavailable.
+func (ns NSSwitch) ProtocolChain(sources map[string]ProtocolSource) ProtocolChain {
+	return buildChain(ns.sourcesFor("protocols"), sources)
+}
+
+func buildChain[S any](nssources []NSSwitchSource, sources map[string]S) []Link[S] {
+	links := make([]Link[S], 0, len(nssources))
+	for _, nssource := range nssources {
+		links = append(links, Link[S]{
+			Name:     nssource.Name,
+			Source:   sources[nssource.Name],
+			Criteria: nssource.Criteria,
+		})
+	}
+	return links
+}
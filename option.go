@@ -0,0 +1,372 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// HeaderOption describes an IPv4 option, an IPv6 Hop-by-Hop or Destination
+// option, or a TCP option kind, by its name, its (8 bit) number as appearing in
+// packets, and the reference(s) defining it, such as "[RFC791]".
+//
+// For IPv4 options, the number is the complete option type octet including the
+// copied flag and option class. For IPv6 options, the number is the complete
+// option type octet including the action and change bits.
+type HeaderOption struct {
+	Name        string   // Option name, such as "RTRALT" or "MSS".
+	Number      uint8    // Option number value.
+	Aliases     []string // List of aliases.
+	Description string   // Option description.
+	Reference   string   // Reference(s), such as "[RFC791]".
+}
+
+// HeaderOptionIndex indexes the known options of either IPv4, IPv6, or TCP by
+// either name (native as well as aliases) and by number.
+//
+// When multiple options share the same name or number, the Precedence rule
+// decides which option becomes the primary one. The Precedence rule needs to be
+// set before merging any options.
+type HeaderOptionIndex struct {
+	Names      map[string]*HeaderOption // Index by option name, including aliases.
+	Numbers    map[uint8]*HeaderOption  // Index by option number.
+	Precedence Precedence               // Rule for picking the primary option.
+
+	entries []*HeaderOption // all merged options in definition order.
+}
+
+// NewHeaderOptionIndex returns a HeaderOptionIndex object initialized with the
+// specified options.
+func NewHeaderOptionIndex(options []HeaderOption) HeaderOptionIndex {
+	i := HeaderOptionIndex{
+		Names:   map[string]*HeaderOption{},
+		Numbers: map[uint8]*HeaderOption{},
+	}
+	i.Merge(options)
+	return i
+}
+
+// LoadHeaderOptions returns a HeaderOptionIndex object initialized from the
+// option definitions in the named file.
+func LoadHeaderOptions(name string) (HeaderOptionIndex, error) {
+	return LoadHeaderOptionsWithOptions(name, ParseOptions{})
+}
+
+// LoadHeaderOptionsWithOptions returns a HeaderOptionIndex object initialized
+// from the option definitions in the named file, parsing it as specified by the
+// options. If the options don't specify a file name, then the specified name is
+// used. In ParseCollectAll mode, the returned index contains all well-formed
+// definitions, even when a ParseErrors error is returned.
+func LoadHeaderOptionsWithOptions(name string, opts ParseOptions) (HeaderOptionIndex, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return NewHeaderOptionIndex(nil), err
+	}
+	defer f.Close()
+	if opts.Filename == "" {
+		opts.Filename = name
+	}
+	options, err := ParseHeaderOptionsWithOptions(f, opts)
+	if options == nil {
+		return NewHeaderOptionIndex(nil), err
+	}
+	return NewHeaderOptionIndex(options), err
+}
+
+// Merge a list of HeaderOption descriptions into the current options index,
+// potentially overriding existing entries in the index in case of duplicates,
+// depending on the index's Precedence rule.
+func (i *HeaderOptionIndex) Merge(options []HeaderOption) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for idx, option := range options {
+		setPrimary(i.Names, option.Name, &options[idx], firstWins)
+		for _, alias := range option.Aliases {
+			setPrimary(i.Names, alias, &options[idx], firstWins)
+		}
+		setPrimary(i.Numbers, option.Number, &options[idx], firstWins)
+		i.entries = append(i.entries, &options[idx])
+	}
+}
+
+// MergeIndex merges another HeaderOptionIndex into the current index,
+// potentially overriding existing entries in case of duplicates, depending on
+// the index's Precedence rule.
+func (i *HeaderOptionIndex) MergeIndex(oi HeaderOptionIndex) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for name, option := range oi.Names {
+		setPrimary(i.Names, name, option, firstWins)
+	}
+	for number, option := range oi.Numbers {
+		setPrimary(i.Numbers, number, option, firstWins)
+	}
+	i.entries = append(i.entries, oi.entries...)
+}
+
+// init initializes the index maps, if not already done.
+func (i *HeaderOptionIndex) init() {
+	if i.Names == nil {
+		i.Names = map[string]*HeaderOption{}
+	}
+	if i.Numbers == nil {
+		i.Numbers = map[uint8]*HeaderOption{}
+	}
+}
+
+// ByName returns the HeaderOption for the specified (alias) name, or nil if not
+// found.
+func (i *HeaderOptionIndex) ByName(name string) *HeaderOption {
+	return i.Names[name]
+}
+
+// ByNumber returns the HeaderOption for the specified option number, or nil if
+// not found.
+func (i *HeaderOptionIndex) ByNumber(number uint8) *HeaderOption {
+	return i.Numbers[number]
+}
+
+// AllByName returns all options with the specified (alias) name in the order of
+// their original definitions, including options overridden by later merges.
+func (i *HeaderOptionIndex) AllByName(name string) []*HeaderOption {
+	var options []*HeaderOption
+	for option := range i.All() {
+		if option.Name == name || slices.Contains(option.Aliases, name) {
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+// AllByNumber returns all options with the specified option number in the order
+// of their original definitions, including options overridden by later merges.
+func (i *HeaderOptionIndex) AllByNumber(number uint8) []*HeaderOption {
+	var options []*HeaderOption
+	for option := range i.All() {
+		if option.Number == number {
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+// All returns an iterator over all options merged into this index, in the order
+// of their original definitions. Each option is yielded only once, regardless
+// of its aliases, and including options that have been overridden by later
+// merges.
+func (i *HeaderOptionIndex) All() iter.Seq[*HeaderOption] {
+	return allOf(i.entries)
+}
+
+// ParseHeaderOptions parses option definitions from the given Reader and
+// returns them as a list of HeaderOption(s). Incomplete definitions are
+// silently skipped, while invalid option numbers result in an error.
+func ParseHeaderOptions(r io.Reader) ([]HeaderOption, error) {
+	return ParseHeaderOptionsWithOptions(r, ParseOptions{})
+}
+
+// ParseHeaderOptionsWithOptions parses option definitions from the given Reader
+// as specified by the options and returns them as a list of HeaderOption(s).
+//
+// Similar to protocols(5), each line defines an option by its name, its number,
+// and optional aliases. Numbers can be decimal or hexadecimal with a "0x"
+// prefix. A trailing comment contains the option's description, followed by its
+// references in brackets, such as:
+//
+//	RTRALT  148  # Router Alert [RFC2113]
+func ParseHeaderOptionsWithOptions(r io.Reader, opts ParseOptions) ([]HeaderOption, error) {
+	options := []HeaderOption{}
+	lp := lineParser{opts: opts}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lp.next()
+		line := strings.TrimSpace(scanner.Text())
+		definition, comment := splitComment(line)
+		fields := strings.Fields(definition)
+		if len(fields) == 0 {
+			continue // skip empty lines and comment lines.
+		}
+		if len(fields) < 2 {
+			if err := lp.malformed(line, "missing option number", false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		number, err := strconv.ParseUint(fields[1], 0, 8)
+		if err != nil {
+			if err := lp.malformed(line, "invalid option number", true); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		description, reference := splitReference(comment)
+		options = append(options, HeaderOption{
+			Name:        fields[0],
+			Number:      uint8(number),
+			Aliases:     fields[2:],
+			Description: description,
+			Reference:   reference,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return options, lp.err()
+}
+
+// splitReference splits a comment into its description and its trailing
+// references in brackets, such as "[RFC791][RFC6814]".
+func splitReference(comment string) (description string, reference string) {
+	rest := comment
+	for strings.HasSuffix(rest, "]") {
+		open := strings.LastIndex(rest, "[")
+		if open < 0 {
+			break
+		}
+		rest = strings.TrimRight(rest[:open], " \t")
+	}
+	return rest, strings.Join(strings.Fields(comment[len(rest):]), "")
+}
+
+// IPOptionByName returns the IPv4 option details for the specified (native or
+// aliased) name, or nil if not defined.
+func IPOptionByName(name string) *HeaderOption {
+	idx := defaultIPOptions.rlock()
+	defer defaultIPOptions.runlock()
+	return idx.ByName(name)
+}
+
+// IPOptionByNumber returns the IPv4 option details for the specified option
+// type octet, or nil if not defined.
+func IPOptionByNumber(number uint8) *HeaderOption {
+	idx := defaultIPOptions.rlock()
+	defer defaultIPOptions.runlock()
+	return idx.ByNumber(number)
+}
+
+// IPv6OptionByName returns the IPv6 Hop-by-Hop and Destination option details
+// for the specified (native or aliased) name, or nil if not defined.
+func IPv6OptionByName(name string) *HeaderOption {
+	idx := defaultIPv6Options.rlock()
+	defer defaultIPv6Options.runlock()
+	return idx.ByName(name)
+}
+
+// IPv6OptionByNumber returns the IPv6 Hop-by-Hop and Destination option details
+// for the specified option type octet, or nil if not defined.
+func IPv6OptionByNumber(number uint8) *HeaderOption {
+	idx := defaultIPv6Options.rlock()
+	defer defaultIPv6Options.runlock()
+	return idx.ByNumber(number)
+}
+
+// TCPOptionByName returns the TCP option details for the specified (native or
+// aliased) name, or nil if not defined.
+func TCPOptionByName(name string) *HeaderOption {
+	idx := defaultTCPOptions.rlock()
+	defer defaultTCPOptions.runlock()
+	return idx.ByName(name)
+}
+
+// TCPOptionByNumber returns the TCP option details for the specified option
+// kind, or nil if not defined.
+func TCPOptionByNumber(number uint8) *HeaderOption {
+	idx := defaultTCPOptions.rlock()
+	defer defaultTCPOptions.runlock()
+	return idx.ByNumber(number)
+}
+
+// AllIPOptions returns an iterator over all IPv4 options in the IPv4 options
+// index, in the order of their original definitions.
+func AllIPOptions() iter.Seq[*HeaderOption] {
+	idx := defaultIPOptions.rlock()
+	defer defaultIPOptions.runlock()
+	return idx.All()
+}
+
+// AllIPv6Options returns an iterator over all IPv6 options in the IPv6 options
+// index, in the order of their original definitions.
+func AllIPv6Options() iter.Seq[*HeaderOption] {
+	idx := defaultIPv6Options.rlock()
+	defer defaultIPv6Options.runlock()
+	return idx.All()
+}
+
+// AllTCPOptions returns an iterator over all TCP options in the TCP options
+// index, in the order of their original definitions.
+func AllTCPOptions() iter.Seq[*HeaderOption] {
+	idx := defaultTCPOptions.rlock()
+	defer defaultTCPOptions.runlock()
+	return idx.All()
+}
+
+// SetIPOptions atomically replaces the IPv4 options index with the specified
+// index. It is safe to call SetIPOptions while lookups are in flight in other
+// goroutines; these lookups finish on the old index. Please note that
+// HeaderOption objects returned from the old index stay valid.
+func SetIPOptions(i HeaderOptionIndex) {
+	defaultIPOptions.set(i)
+}
+
+// SetIPv6Options atomically replaces the IPv6 options index with the specified
+// index. It is safe to call SetIPv6Options while lookups are in flight in other
+// goroutines; these lookups finish on the old index. Please note that
+// HeaderOption objects returned from the old index stay valid.
+func SetIPv6Options(i HeaderOptionIndex) {
+	defaultIPv6Options.set(i)
+}
+
+// SetTCPOptions atomically replaces the TCP options index with the specified
+// index. It is safe to call SetTCPOptions while lookups are in flight in other
+// goroutines; these lookups finish on the old index. Please note that
+// HeaderOption objects returned from the old index stay valid.
+func SetTCPOptions(i HeaderOptionIndex) {
+	defaultTCPOptions.set(i)
+}
+
+// IPOptions is the index of IPv4 option names and numbers. If left to the zero
+// value, then it will be automatically initialized with the builtin definitions
+// upon first use of IPOptionByName or IPOptionByNumber. This initialization is
+// goroutine-safe.
+//
+// Directly assigning to IPOptions or merging into it is not safe while lookups
+// are in flight; use SetIPOptions instead.
+var IPOptions HeaderOptionIndex
+
+// IPv6Options is the index of IPv6 Hop-by-Hop and Destination option names and
+// numbers. If left to the zero value, then it will be automatically initialized
+// with the builtin definitions upon first use of IPv6OptionByName or
+// IPv6OptionByNumber. This initialization is goroutine-safe.
+//
+// Directly assigning to IPv6Options or merging into it is not safe while
+// lookups are in flight; use SetIPv6Options instead.
+var IPv6Options HeaderOptionIndex
+
+// TCPOptions is the index of TCP option kind names and numbers. If left to the
+// zero value, then it will be automatically initialized with the builtin
+// definitions upon first use of TCPOptionByName or TCPOptionByNumber. This
+// initialization is goroutine-safe.
+//
+// Directly assigning to TCPOptions or merging into it is not safe while lookups
+// are in flight; use SetTCPOptions instead.
+var TCPOptions HeaderOptionIndex
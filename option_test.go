@@ -0,0 +1,187 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("IP, IPv6, and TCP options", func() {
+
+	Context("parsing descriptions", func() {
+
+		It("returns correct descriptions", func() {
+			o, err := ParseHeaderOptions(strings.NewReader(`
+# A comment
+RTRALT	148	RA # Router Alert [RFC2113]
+Jumbo   0xc2
+TR	82 # Traceroute [RFC1393][RFC6814]
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(o).To(HaveExactElements(
+				MatchFields(IgnoreExtras, Fields{
+					"Name":        Equal("RTRALT"),
+					"Number":      Equal(uint8(148)),
+					"Aliases":     ConsistOf("RA"),
+					"Description": Equal("Router Alert"),
+					"Reference":   Equal("[RFC2113]"),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Name":        Equal("Jumbo"),
+					"Number":      Equal(uint8(0xc2)),
+					"Aliases":     BeEmpty(),
+					"Description": BeEmpty(),
+					"Reference":   BeEmpty(),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Name":        Equal("TR"),
+					"Description": Equal("Traceroute"),
+					"Reference":   Equal("[RFC1393][RFC6814]"),
+				}),
+			))
+		})
+
+		It("splits references from descriptions", func() {
+			Expect(splitReference("")).To(BeEmpty())
+			d, r := splitReference("Router Alert")
+			Expect(d).To(Equal("Router Alert"))
+			Expect(r).To(BeEmpty())
+			d, r = splitReference("[RFC8200]")
+			Expect(d).To(BeEmpty())
+			Expect(r).To(Equal("[RFC8200]"))
+			d, r = splitReference("Stream ID [RFC791] [RFC6814]")
+			Expect(d).To(Equal("Stream ID"))
+			Expect(r).To(Equal("[RFC791][RFC6814]"))
+			d, r = splitReference("Ullmann IPv7]")
+			Expect(d).To(Equal("Ullmann IPv7]"))
+			Expect(r).To(BeEmpty())
+		})
+
+		It("skips incomplete definitions", func() {
+			o, err := ParseHeaderOptions(strings.NewReader("foo\nbar 1\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(o).To(HaveExactElements(HaveField("Name", "bar")))
+		})
+
+		It("rejects invalid option numbers", func() {
+			_, err := ParseHeaderOptions(strings.NewReader("foo 256\n"))
+			Expect(err).To(MatchError(ContainSubstring("invalid option number")))
+			_, err = ParseHeaderOptions(strings.NewReader("foo bar\n"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("collects all errors", func() {
+			o, err := ParseHeaderOptionsWithOptions(strings.NewReader("foo\nbar baz\nNOP 1\n"),
+				ParseOptions{Mode: ParseCollectAll})
+			Expect(err).To(HaveLen(2))
+			Expect(o).To(HaveExactElements(HaveField("Name", "NOP")))
+		})
+
+	})
+
+	Context("loading", func() {
+
+		It("reports an error for a non-existing file", func() {
+			_, err := LoadHeaderOptions("test/non-existing-options")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("loads and indexes", func() {
+			idx, err := LoadHeaderOptions("test/tcp-options")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(slices.Collect(idx.All())).To(HaveLen(5))
+			Expect(idx.ByName("WSCALE").Number).To(Equal(uint8(3)))
+			Expect(idx.ByNumber(0xfd).Name).To(Equal("EXP"))
+			Expect(idx.ByNumber(42)).To(BeNil())
+			Expect(idx.ByName("MSS").Reference).To(Equal("[RFC9293]"))
+		})
+
+		It("merges", func() {
+			idx, err := LoadHeaderOptions("test/tcp-options")
+			Expect(err).NotTo(HaveOccurred())
+			idx.MergeIndex(NewHeaderOptionIndex([]HeaderOption{
+				{Name: "EXP", Number: 0xfe},
+			}))
+			Expect(idx.ByName("EXP").Number).To(Equal(uint8(0xfe)))
+			Expect(idx.ByNumber(0xfd).Name).To(Equal("EXP"))
+			Expect(idx.AllByName("EXP")).To(HaveExactElements(
+				HaveField("Number", uint8(0xfd)),
+				HaveField("Number", uint8(0xfe)),
+			))
+			Expect(idx.AllByNumber(3)).To(HaveLen(1))
+		})
+
+	})
+
+	Context("package-level lookups", func() {
+
+		BeforeEach(func() {
+			IPOptions = HeaderOptionIndex{}
+			IPv6Options = HeaderOptionIndex{}
+			TCPOptions = HeaderOptionIndex{}
+			DeferCleanup(func() {
+				IPOptions = HeaderOptionIndex{}
+				IPv6Options = HeaderOptionIndex{}
+				TCPOptions = HeaderOptionIndex{}
+			})
+		})
+
+		It("looks up builtin IPv4 options", func() {
+			Expect(IPOptionByName("RTRALT")).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Number":    Equal(uint8(148)),
+				"Reference": Equal("[RFC2113]"),
+			})))
+			Expect(IPOptionByNumber(131).Name).To(Equal("LSR"))
+			Expect(IPOptionByNumber(2)).To(BeNil())
+			Expect(slices.Collect(AllIPOptions())).To(HaveLen(len(BuiltinIPOptions)))
+		})
+
+		It("looks up builtin IPv6 options", func() {
+			Expect(IPv6OptionByName("Jumbo").Number).To(Equal(uint8(0xc2)))
+			Expect(IPv6OptionByNumber(0x05).Name).To(Equal("RTRALT"))
+			Expect(IPv6OptionByNumber(0x01).Reference).To(Equal("[RFC8200]"))
+			Expect(slices.Collect(AllIPv6Options())).To(HaveLen(len(BuiltinIPv6Options)))
+		})
+
+		It("looks up builtin TCP options", func() {
+			Expect(TCPOptionByName("MSS").Number).To(Equal(uint8(2)))
+			Expect(TCPOptionByNumber(30).Name).To(Equal("MPTCP"))
+			Expect(TCPOptionByName("TCP-AO").Number).To(Equal(uint8(29)))
+			Expect(slices.Collect(AllTCPOptions())).To(HaveLen(len(BuiltinTCPOptions)))
+		})
+
+		It("has unique builtin option numbers", func() {
+			for _, options := range [][]HeaderOption{BuiltinIPOptions, BuiltinIPv6Options, BuiltinTCPOptions} {
+				idx := NewHeaderOptionIndex(options)
+				Expect(idx.Numbers).To(HaveLen(len(options)))
+			}
+		})
+
+		It("replaces the index", func() {
+			idx, err := LoadHeaderOptions("test/tcp-options")
+			Expect(err).NotTo(HaveOccurred())
+			SetTCPOptions(idx)
+			Expect(TCPOptionByName("MPTCP")).To(BeNil())
+			Expect(TCPOptionByName("WSCALE")).NotTo(BeNil())
+		})
+
+	})
+
+})
@@ -0,0 +1,443 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MACPrefix is a MAC address prefix of a certain length in bits, such as a
+// 24 bit OUI (Organizationally Unique Identifier) or the 28 and 36 bit
+// prefixes of the IEEE MA-M and MA-S registries. The address bits beyond the
+// prefix length are always zero.
+type MACPrefix struct {
+	Addr [6]byte // Prefix address bits, zero beyond the prefix length.
+	Bits int     // Prefix length in bits.
+}
+
+// ParseMACPrefix parses a MAC address prefix in Wireshark "manuf" notation,
+// such as "00:1B:21" and "00:50:C2:00:00:00/36", or as an IEEE registry
+// assignment, such as "001B21" and "0050C2000". The hex digits can be
+// separated by colons, dashes, or dots. Without an explicit prefix length the
+// prefix length is derived from the number of hex digits.
+func ParseMACPrefix(s string) (MACPrefix, error) {
+	digits, length, hasLength := strings.Cut(s, "/")
+	var p MACPrefix
+	nibbles := 0
+	for _, r := range digits {
+		if r == ':' || r == '-' || r == '.' {
+			continue
+		}
+		nibble, err := strconv.ParseUint(string(r), 16, 4)
+		if err != nil || nibbles >= 2*len(p.Addr) {
+			return MACPrefix{}, errors.New("invalid MAC address prefix")
+		}
+		p.Addr[nibbles/2] |= byte(nibble) << (4 * (1 - nibbles%2))
+		nibbles++
+	}
+	if nibbles == 0 {
+		return MACPrefix{}, errors.New("invalid MAC address prefix")
+	}
+	p.Bits = 4 * nibbles
+	if hasLength {
+		bits, err := strconv.ParseUint(length, 10, 8)
+		if err != nil || bits > 8*uint64(len(p.Addr)) {
+			return MACPrefix{}, errors.New("invalid MAC address prefix length")
+		}
+		p.Bits = int(bits)
+	}
+	return p.masked(), nil
+}
+
+// masked returns the prefix with all address bits beyond the prefix length
+// zeroed.
+func (p MACPrefix) masked() MACPrefix {
+	for idx := range p.Addr {
+		switch bits := p.Bits - 8*idx; {
+		case bits <= 0:
+			p.Addr[idx] = 0
+		case bits < 8:
+			p.Addr[idx] &= ^byte(0xff >> bits)
+		}
+	}
+	return p
+}
+
+// Contains returns true if the specified MAC address lies within this prefix.
+func (p MACPrefix) Contains(mac net.HardwareAddr) bool {
+	if len(mac)*8 < p.Bits {
+		return false
+	}
+	return macPrefixOf(mac, p.Bits) == p
+}
+
+// String returns the prefix in Wireshark "manuf" notation, that is, either as
+// only the prefix octets, such as "00:1B:21", or as a complete MAC address
+// with the prefix length, such as "00:50:C2:00:00:00/36".
+func (p MACPrefix) String() string {
+	if p.Bits%8 == 0 && p.Bits > 0 {
+		return strings.ToUpper(net.HardwareAddr(p.Addr[:p.Bits/8]).String())
+	}
+	return strings.ToUpper(net.HardwareAddr(p.Addr[:]).String()) + "/" + strconv.Itoa(p.Bits)
+}
+
+// macPrefixOf returns the prefix of the specified length of the specified MAC
+// address.
+func macPrefixOf(mac net.HardwareAddr, bits int) MACPrefix {
+	p := MACPrefix{Bits: bits}
+	copy(p.Addr[:], mac)
+	return p.masked()
+}
+
+// OUI describes a block of MAC addresses assigned to an organization, such as
+// an MA-L block with a 24 bit OUI, or a block with a well-known use, such as
+// the IPv4 multicast MAC addresses.
+type OUI struct {
+	Prefix       MACPrefix // Assigned MAC address prefix.
+	Name         string    // Short vendor name.
+	Organization string    // Full organization name, if known.
+	Address      string    // Organization address, if known.
+	Registry     string    // IEEE registry, such as "MA-L", if known.
+}
+
+// OUIIndex indexes MAC address blocks by their prefixes, supporting longest
+// prefix lookups of MAC addresses.
+//
+// When multiple blocks share the same prefix, the Precedence rule decides
+// which block becomes the primary one. The Precedence rule needs to be set
+// before merging any blocks.
+type OUIIndex struct {
+	Prefixes   map[MACPrefix]*OUI // Index by MAC address prefix.
+	Precedence Precedence         // Rule for picking the primary block.
+
+	entries []*OUI // all merged blocks in definition order.
+	lengths []int  // distinct prefix lengths, longest first.
+}
+
+// NewOUIIndex returns an OUIIndex object initialized with the specified MAC
+// address blocks.
+func NewOUIIndex(ouis []OUI) OUIIndex {
+	i := OUIIndex{
+		Prefixes: map[MACPrefix]*OUI{},
+	}
+	i.Merge(ouis)
+	return i
+}
+
+// LoadManuf returns an OUIIndex object initialized from the MAC address
+// blocks in the named file in Wireshark "manuf" format.
+func LoadManuf(name string) (OUIIndex, error) {
+	return LoadManufWithOptions(name, ParseOptions{})
+}
+
+// LoadManufWithOptions returns an OUIIndex object initialized from the MAC
+// address blocks in the named file in Wireshark "manuf" format, parsing it as
+// specified by the options. If the options don't specify a file name, then the
+// specified name is used. In ParseCollectAll mode, the returned index contains
+// all well-formed definitions, even when a ParseErrors error is returned.
+func LoadManufWithOptions(name string, opts ParseOptions) (OUIIndex, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return NewOUIIndex(nil), err
+	}
+	defer f.Close()
+	if opts.Filename == "" {
+		opts.Filename = name
+	}
+	ouis, err := ParseManufWithOptions(f, opts)
+	if ouis == nil {
+		return NewOUIIndex(nil), err
+	}
+	return NewOUIIndex(ouis), err
+}
+
+// Merge a list of OUI descriptions into the current index, potentially
+// overriding existing entries in the index in case of duplicates, depending
+// on the index's Precedence rule.
+func (i *OUIIndex) Merge(ouis []OUI) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for idx, oui := range ouis {
+		prefix := oui.Prefix.masked()
+		setPrimary(i.Prefixes, prefix, &ouis[idx], firstWins)
+		i.addLength(prefix.Bits)
+		i.entries = append(i.entries, &ouis[idx])
+	}
+}
+
+// MergeIndex merges another OUIIndex into the current index, potentially
+// overriding existing entries in case of duplicates, depending on the index's
+// Precedence rule.
+func (i *OUIIndex) MergeIndex(oi OUIIndex) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for prefix, oui := range oi.Prefixes {
+		setPrimary(i.Prefixes, prefix, oui, firstWins)
+		i.addLength(prefix.Bits)
+	}
+	i.entries = append(i.entries, oi.entries...)
+}
+
+// init initializes the index map, if not already done.
+func (i *OUIIndex) init() {
+	if i.Prefixes == nil {
+		i.Prefixes = map[MACPrefix]*OUI{}
+	}
+}
+
+// addLength adds the specified prefix length to the distinct prefix lengths
+// to try when looking up MAC addresses, keeping the longest first.
+func (i *OUIIndex) addLength(bits int) {
+	if slices.Contains(i.lengths, bits) {
+		return
+	}
+	i.lengths = append(i.lengths, bits)
+	slices.SortFunc(i.lengths, func(a, b int) int { return b - a })
+}
+
+// ByPrefix returns the OUI for the specified MAC address prefix, or nil if not
+// found. The prefix must match exactly.
+func (i *OUIIndex) ByPrefix(prefix MACPrefix) *OUI {
+	return i.Prefixes[prefix.masked()]
+}
+
+// ByAddr returns the OUI with the longest prefix matching the specified MAC
+// address, or nil if not found. For addresses longer than 48 bits, such as
+// EUI-64 addresses, only their first 48 bits are taken into account.
+func (i *OUIIndex) ByAddr(mac net.HardwareAddr) *OUI {
+	for _, bits := range i.lengths {
+		if len(mac)*8 < bits {
+			continue
+		}
+		if oui := i.Prefixes[macPrefixOf(mac, bits)]; oui != nil {
+			return oui
+		}
+	}
+	return nil
+}
+
+// All returns an iterator over all MAC address blocks merged into this index,
+// in the order of their original definitions, including blocks that have been
+// overridden by later merges.
+func (i *OUIIndex) All() iter.Seq[*OUI] {
+	return allOf(i.entries)
+}
+
+// ParseManuf parses MAC address blocks in Wireshark "manuf" format from the
+// given Reader and returns them as a list of OUI(s). Incomplete definitions
+// are silently skipped, while invalid prefixes result in an error.
+func ParseManuf(r io.Reader) ([]OUI, error) {
+	return ParseManufWithOptions(r, ParseOptions{})
+}
+
+// ParseManufWithOptions parses MAC address blocks in Wireshark "manuf" format
+// from the given Reader as specified by the options and returns them as a list
+// of OUI(s).
+//
+// Each line defines a block by its prefix, a short vendor name, and an
+// optional full organization name. Older "manuf" files put the organization
+// name into a trailing comment instead, which is supported too:
+//
+//	00:00:0C	Cisco	Cisco Systems, Inc
+//	00:00:01	Xerox	# XEROX CORPORATION
+func ParseManufWithOptions(r io.Reader, opts ParseOptions) ([]OUI, error) {
+	ouis := []OUI{}
+	lp := lineParser{opts: opts}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lp.next()
+		line := strings.TrimSpace(scanner.Text())
+		definition, comment := splitComment(line)
+		prefix, rest := cutField(definition)
+		if prefix == "" {
+			continue // skip empty lines and comment lines.
+		}
+		name, organization := cutField(rest)
+		if name == "" {
+			if err := lp.malformed(line, "missing vendor name", false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		p, err := ParseMACPrefix(prefix)
+		if err != nil {
+			if err := lp.malformed(line, err.Error(), true); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if organization == "" {
+			organization = comment
+		}
+		ouis = append(ouis, OUI{
+			Prefix:       p,
+			Name:         name,
+			Organization: organization,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return ouis, lp.err()
+}
+
+// cutField returns the first whitespace-separated field of s, as well as the
+// remaining text after it with surrounding whitespace removed.
+func cutField(s string) (field, rest string) {
+	s = strings.TrimSpace(s)
+	end := strings.IndexFunc(s, unicode.IsSpace)
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], strings.TrimSpace(s[end:])
+}
+
+// ParseIEEEOUICSV parses the CSV form of an IEEE MAC address block registry,
+// such as MA-L, MA-M, or MA-S, from the given Reader and returns its entries as
+// a list of OUI(s). The registries are available from
+// https://standards-oui.ieee.org/oui/oui.csv,
+// https://standards-oui.ieee.org/oui28/mam.csv, and
+// https://standards-oui.ieee.org/oui36/oui36.csv.
+//
+// As the IEEE registries don't define short vendor names, the OUI Name(s) are
+// set to the full organization names.
+func ParseIEEEOUICSV(r io.Reader) ([]OUI, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := map[string]int{}
+	for idx, column := range header {
+		columns[strings.TrimSpace(column)] = idx
+	}
+	for _, column := range []string{"Assignment", "Organization Name"} {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("IEEE registry CSV lacks %q column", column)
+		}
+	}
+	field := func(row []string, column string) string {
+		if idx, ok := columns[column]; ok && idx < len(row) {
+			return strings.TrimSpace(row[idx])
+		}
+		return ""
+	}
+
+	ouis := []OUI{}
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		assignment := field(row, "Assignment")
+		p, err := ParseMACPrefix(assignment)
+		if err != nil || strings.Contains(assignment, "/") {
+			return nil, fmt.Errorf("invalid assignment %q in IEEE registry", assignment)
+		}
+		organization := field(row, "Organization Name")
+		ouis = append(ouis, OUI{
+			Prefix:       p,
+			Name:         organization,
+			Organization: organization,
+			Address:      field(row, "Organization Address"),
+			Registry:     field(row, "Registry"),
+		})
+	}
+	return ouis, nil
+}
+
+// IsMulticastMAC returns true if the specified MAC address is a group
+// (multicast or broadcast) address, that is, its I/G bit is set.
+func IsMulticastMAC(mac net.HardwareAddr) bool {
+	return len(mac) > 0 && mac[0]&0x01 != 0
+}
+
+// IsUnicastMAC returns true if the specified MAC address is an individual
+// (unicast) address, that is, its I/G bit is cleared.
+func IsUnicastMAC(mac net.HardwareAddr) bool {
+	return len(mac) > 0 && mac[0]&0x01 == 0
+}
+
+// IsLocalMAC returns true if the specified MAC address is locally
+// administered, that is, its U/L bit is set. Locally administered addresses
+// don't belong to any assigned MAC address block, except for IEEE CIDs.
+func IsLocalMAC(mac net.HardwareAddr) bool {
+	return len(mac) > 0 && mac[0]&0x02 != 0
+}
+
+// IsUniversalMAC returns true if the specified MAC address is universally
+// administered, that is, its U/L bit is cleared.
+func IsUniversalMAC(mac net.HardwareAddr) bool {
+	return len(mac) > 0 && mac[0]&0x02 == 0
+}
+
+// OUIByAddr returns the OUI details of the MAC address block with the longest
+// prefix matching the specified MAC address, or nil if not found.
+func OUIByAddr(mac net.HardwareAddr) *OUI {
+	idx := defaultOUIs.rlock()
+	defer defaultOUIs.runlock()
+	return idx.ByAddr(mac)
+}
+
+// OUIByPrefix returns the OUI details for the specified MAC address prefix, or
+// nil if not found.
+func OUIByPrefix(prefix MACPrefix) *OUI {
+	idx := defaultOUIs.rlock()
+	defer defaultOUIs.runlock()
+	return idx.ByPrefix(prefix)
+}
+
+// AllOUIs returns an iterator over all MAC address blocks in the OUI index, in
+// the order of their original definitions.
+func AllOUIs() iter.Seq[*OUI] {
+	idx := defaultOUIs.rlock()
+	defer defaultOUIs.runlock()
+	return idx.All()
+}
+
+// SetOUIs atomically replaces the OUI index with the specified index. It is
+// safe to call SetOUIs while lookups are in flight in other goroutines; these
+// lookups finish on the old index. Please note that OUI objects returned from
+// the old index stay valid.
+func SetOUIs(i OUIIndex) {
+	defaultOUIs.set(i)
+}
+
+// OUIs is the index of MAC address blocks and their vendors. If left to the
+// zero value, then it will be automatically initialized with the builtin
+// definitions upon first use of OUIByAddr, OUIByPrefix, et cetera. This
+// initialization is goroutine-safe. The builtin definitions consist of the
+// embedded IEEE MA-L, MA-M, and MA-S registries and BuiltinOUIs.
+//
+// Directly assigning to OUIs or merging into it is not safe while lookups are
+// in flight; use SetOUIs instead.
+var OUIs OUIIndex
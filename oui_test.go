@@ -0,0 +1,283 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net"
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+func mustParseMAC(s string) net.HardwareAddr {
+	GinkgoHelper()
+	mac, err := net.ParseMAC(s)
+	Expect(err).NotTo(HaveOccurred())
+	return mac
+}
+
+func mustParseMACPrefix(s string) MACPrefix {
+	GinkgoHelper()
+	p, err := ParseMACPrefix(s)
+	Expect(err).NotTo(HaveOccurred())
+	return p
+}
+
+var _ = Describe("OUIs", func() {
+
+	Context("MAC address prefixes", func() {
+
+		DescribeTable("parses prefixes",
+			func(s string, expected MACPrefix, str string) {
+				p, err := ParseMACPrefix(s)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(p).To(Equal(expected))
+				Expect(p.String()).To(Equal(str))
+			},
+			Entry(nil, "00:1b:21", MACPrefix{Addr: [6]byte{0x00, 0x1b, 0x21}, Bits: 24}, "00:1B:21"),
+			Entry(nil, "00-1B-21", MACPrefix{Addr: [6]byte{0x00, 0x1b, 0x21}, Bits: 24}, "00:1B:21"),
+			Entry(nil, "001B21", MACPrefix{Addr: [6]byte{0x00, 0x1b, 0x21}, Bits: 24}, "00:1B:21"),
+			Entry(nil, "0050C2001", MACPrefix{Addr: [6]byte{0x00, 0x50, 0xc2, 0x00, 0x10}, Bits: 36}, "00:50:C2:00:10:00/36"),
+			Entry(nil, "00:50:C2:00:10:00/36", MACPrefix{Addr: [6]byte{0x00, 0x50, 0xc2, 0x00, 0x10}, Bits: 36}, "00:50:C2:00:10:00/36"),
+			Entry(nil, "01:00:5E:7F:FF:FF/25", MACPrefix{Addr: [6]byte{0x01, 0x00, 0x5e, 0x00}, Bits: 25}, "01:00:5E:00:00:00/25"),
+			Entry(nil, "0123.4567.89ab", MACPrefix{Addr: [6]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab}, Bits: 48}, "01:23:45:67:89:AB"),
+		)
+
+		DescribeTable("rejects invalid prefixes",
+			func(s string) {
+				Expect(ParseMACPrefix(s)).Error().To(HaveOccurred())
+			},
+			Entry(nil, ""),
+			Entry(nil, "::"),
+			Entry(nil, "00:1G:21"),
+			Entry(nil, "00:11:22:33:44:55:66"),
+			Entry(nil, "00:11:22/49"),
+			Entry(nil, "00:11:22/x"),
+		)
+
+		It("checks containment", func() {
+			p := mustParseMACPrefix("00:50:C2:00:10:00/36")
+			Expect(p.Contains(mustParseMAC("00:50:c2:00:1a:bc"))).To(BeTrue())
+			Expect(p.Contains(mustParseMAC("00:50:c2:00:2a:bc"))).To(BeFalse())
+			Expect(p.Contains(net.HardwareAddr{0x00, 0x50})).To(BeFalse())
+		})
+
+	})
+
+	Context("parsing", func() {
+
+		It("returns correct manuf descriptions", func() {
+			o, err := ParseManuf(strings.NewReader(`
+# A comment
+00:00:0C	Cisco	Cisco Systems, Inc
+00:00:01	Xerox	# XEROX CORPORATION
+00:50:C2:00:10:00/36	Bar
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(o).To(HaveExactElements(
+				MatchFields(IgnoreExtras, Fields{
+					"Prefix":       Equal(mustParseMACPrefix("00:00:0c")),
+					"Name":         Equal("Cisco"),
+					"Organization": Equal("Cisco Systems, Inc"),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Name":         Equal("Xerox"),
+					"Organization": Equal("XEROX CORPORATION"),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Prefix":       Equal(mustParseMACPrefix("0050C2001")),
+					"Name":         Equal("Bar"),
+					"Organization": BeEmpty(),
+				}),
+			))
+		})
+
+		It("skips incomplete manuf definitions", func() {
+			o, err := ParseManuf(strings.NewReader("00:00:0C\n00:00:01 Xerox\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(o).To(HaveExactElements(HaveField("Name", "Xerox")))
+		})
+
+		It("rejects invalid manuf prefixes", func() {
+			_, err := ParseManuf(strings.NewReader("00:00:0X Foo\n"))
+			Expect(err).To(MatchError(ContainSubstring("invalid MAC address prefix")))
+		})
+
+		It("collects all manuf errors", func() {
+			o, err := ParseManufWithOptions(strings.NewReader("00:00:0C\n00:00:0X Foo\n00:00:01 Xerox\n"),
+				ParseOptions{Mode: ParseCollectAll})
+			Expect(err).To(HaveLen(2))
+			Expect(o).To(HaveExactElements(HaveField("Name", "Xerox")))
+		})
+
+		It("parses IEEE registry CSV", func() {
+			o, err := ParseIEEEOUICSV(strings.NewReader(`Registry,Assignment,Organization Name,Organization Address
+MA-L,00000C,"Cisco Systems, Inc",170 WEST TASMAN DRIVE SAN JOSE CA US 95134-1706 
+MA-M,70B3D5F,Foo Corp.,
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(o).To(HaveExactElements(
+				MatchFields(IgnoreExtras, Fields{
+					"Prefix":       Equal(mustParseMACPrefix("00:00:0C")),
+					"Name":         Equal("Cisco Systems, Inc"),
+					"Organization": Equal("Cisco Systems, Inc"),
+					"Address":      Equal("170 WEST TASMAN DRIVE SAN JOSE CA US 95134-1706"),
+					"Registry":     Equal("MA-L"),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Prefix":   Equal(MACPrefix{Addr: [6]byte{0x70, 0xb3, 0xd5, 0xf0}, Bits: 28}),
+					"Registry": Equal("MA-M"),
+				}),
+			))
+		})
+
+		It("rejects invalid IEEE registry CSV", func() {
+			Expect(ParseIEEEOUICSV(strings.NewReader(""))).Error().To(HaveOccurred())
+			Expect(ParseIEEEOUICSV(strings.NewReader("Registry,Organization Name\n"))).Error().To(
+				MatchError(ContainSubstring(`lacks "Assignment" column`)))
+			Expect(ParseIEEEOUICSV(strings.NewReader("Assignment,Organization Name\n00:00:0C/24,Foo\n"))).Error().To(
+				MatchError(ContainSubstring("invalid assignment")))
+			Expect(ParseIEEEOUICSV(strings.NewReader("Assignment,Organization Name\n\"foo\n"))).Error().To(
+				HaveOccurred())
+		})
+
+		It("decodes the compressed IEEE registries table", func() {
+			var table bytes.Buffer
+			zw := gzip.NewWriter(&table)
+			_, _ = zw.Write([]byte("Registry,Assignment,Organization Name\nMA-S,70B3D5123,Foo Corp.\n"))
+			Expect(zw.Close()).To(Succeed())
+			Expect(decodeIEEEOUIs(table.Bytes())).To(HaveExactElements(
+				MatchFields(IgnoreExtras, Fields{
+					"Prefix":       Equal(MACPrefix{Addr: [6]byte{0x70, 0xb3, 0xd5, 0x12, 0x30}, Bits: 36}),
+					"Organization": Equal("Foo Corp."),
+					"Registry":     Equal("MA-S"),
+				})))
+
+			Expect(decodeIEEEOUIs([]byte("Registry,Assignment"))).Error().To(HaveOccurred())
+			Expect(decodeIEEEOUIs(builtinIEEEOUIsCSV)).Error().NotTo(HaveOccurred())
+		})
+
+	})
+
+	Context("indexing", func() {
+
+		It("reports an error for a non-existing file", func() {
+			_, err := LoadManuf("test/non-existing-manuf")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("looks up the longest matching prefix", func() {
+			idx, err := LoadManuf("test/manuf")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(slices.Collect(idx.All())).To(HaveLen(6))
+			Expect(idx.ByAddr(mustParseMAC("00:00:0c:12:34:56")).Name).To(Equal("Cisco"))
+			Expect(idx.ByAddr(mustParseMAC("00:50:c2:00:0f:ff")).Name).To(Equal("Foo"))
+			Expect(idx.ByAddr(mustParseMAC("00:50:c2:00:10:00")).Name).To(Equal("Bar"))
+			Expect(idx.ByAddr(mustParseMAC("00:50:c2:00:20:00")).Name).To(Equal("IeeeRegi"))
+			Expect(idx.ByAddr(mustParseMAC("01:00:5e:7f:00:01")).Name).To(Equal("IPv4mcast"))
+			Expect(idx.ByAddr(mustParseMAC("01:00:5e:80:00:01"))).To(BeNil())
+			Expect(idx.ByAddr(mustParseMAC("00:00:0c:12:34:56:78:9a")).Name).To(Equal("Cisco"))
+			Expect(idx.ByAddr(net.HardwareAddr{0x00, 0x00})).To(BeNil())
+			Expect(idx.ByPrefix(mustParseMACPrefix("00:50:C2:00:1F:FF/36")).Name).To(Equal("Bar"))
+		})
+
+		It("merges", func() {
+			idx, err := LoadManuf("test/manuf")
+			Expect(err).NotTo(HaveOccurred())
+			ieee, err := ParseIEEEOUICSV(strings.NewReader(`Registry,Assignment,Organization Name,Organization Address
+MA-S,0050C2002,Baz Inc.,
+MA-L,00000C,"Cisco Systems, Inc",
+`))
+			Expect(err).NotTo(HaveOccurred())
+			idx.MergeIndex(NewOUIIndex(ieee))
+			Expect(idx.ByAddr(mustParseMAC("00:50:c2:00:20:00")).Name).To(Equal("Baz Inc."))
+			Expect(idx.ByAddr(mustParseMAC("00:00:0c:00:00:00")).Registry).To(Equal("MA-L"))
+
+			idx = NewOUIIndex(nil)
+			idx.Precedence = FirstWins
+			idx.Merge([]OUI{
+				{Prefix: mustParseMACPrefix("00:00:0C"), Name: "Cisco"},
+				{Prefix: mustParseMACPrefix("00:00:0C"), Name: "Cisco2"},
+			})
+			Expect(idx.ByAddr(mustParseMAC("00:00:0c:00:00:00")).Name).To(Equal("Cisco"))
+		})
+
+	})
+
+	It("classifies MAC addresses", func() {
+		for _, tt := range []struct {
+			mac       string
+			multicast bool
+			local     bool
+		}{
+			{"00:00:0c:12:34:56", false, false},
+			{"01:00:5e:00:00:01", true, false},
+			{"02:42:ac:11:00:02", false, true},
+			{"33:33:00:00:00:01", true, true},
+			{"ff:ff:ff:ff:ff:ff", true, true},
+		} {
+			mac := mustParseMAC(tt.mac)
+			Expect(IsMulticastMAC(mac)).To(Equal(tt.multicast), tt.mac)
+			Expect(IsUnicastMAC(mac)).To(Equal(!tt.multicast), tt.mac)
+			Expect(IsLocalMAC(mac)).To(Equal(tt.local), tt.mac)
+			Expect(IsUniversalMAC(mac)).To(Equal(!tt.local), tt.mac)
+		}
+		Expect(IsMulticastMAC(nil)).To(BeFalse())
+		Expect(IsUnicastMAC(nil)).To(BeFalse())
+		Expect(IsLocalMAC(nil)).To(BeFalse())
+		Expect(IsUniversalMAC(nil)).To(BeFalse())
+	})
+
+	Context("package-level lookups", func() {
+
+		BeforeEach(func() {
+			OUIs = OUIIndex{}
+			DeferCleanup(func() {
+				OUIs = OUIIndex{}
+			})
+		})
+
+		It("looks up builtin OUIs", func() {
+			Expect(OUIByAddr(mustParseMAC("00:50:56:01:02:03"))).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Name":         Equal("VMware"),
+				"Organization": Equal("VMware, Inc."),
+			})))
+			Expect(OUIByAddr(mustParseMAC("33:33:00:00:00:01")).Name).To(Equal("IPv6mcast"))
+			Expect(OUIByAddr(mustParseMAC("ff:ff:ff:ff:ff:ff")).Name).To(Equal("Broadcast"))
+			Expect(OUIByAddr(mustParseMAC("02:42:ac:11:00:02"))).To(BeNil())
+			Expect(OUIByPrefix(mustParseMACPrefix("08:00:27")).Organization).To(Equal("PCS Systemtechnik GmbH"))
+			Expect(len(slices.Collect(AllOUIs()))).To(BeNumerically(">=", len(BuiltinOUIs)))
+		})
+
+		It("has unique builtin prefixes", func() {
+			Expect(NewOUIIndex(BuiltinOUIs).Prefixes).To(HaveLen(len(BuiltinOUIs)))
+		})
+
+		It("replaces the index", func() {
+			idx, err := LoadManuf("test/manuf")
+			Expect(err).NotTo(HaveOccurred())
+			SetOUIs(idx)
+			Expect(OUIByAddr(mustParseMAC("00:50:56:01:02:03"))).To(BeNil())
+			Expect(OUIByAddr(mustParseMAC("00:00:01:01:02:03")).Name).To(Equal("Xerox"))
+		})
+
+	})
+
+})
@@ -0,0 +1,141 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseMode controls how parsers deal with malformed lines.
+type ParseMode int
+
+const (
+	// ParseDefault keeps the historic behavior of the individual parsers:
+	// ParseServices silently skips malformed lines, while ParseProtocols and
+	// ParseEtherTypes skip incomplete lines, but fail on invalid numbers.
+	ParseDefault ParseMode = iota
+	// ParseLenient silently skips all malformed lines.
+	ParseLenient
+	// ParseStrict fails on the first malformed line, returning a *ParseError.
+	ParseStrict
+	// ParseCollectAll skips all malformed lines, returning the well-formed
+	// entries together with a ParseErrors error listing all malformed lines.
+	ParseCollectAll
+)
+
+// ParseOptions controls the parsing of protocols, services, and EtherTypes
+// definitions.
+type ParseOptions struct {
+	Mode     ParseMode // how to deal with malformed lines.
+	Filename string    // name of file being parsed, for use in ParseErrors.
+	// KeepUnknownProtocols keeps services with protocols not found in the
+	// ProtocolIndex, setting their Service.Protocol to nil, instead of
+	// treating them as malformed.
+	KeepUnknownProtocols bool
+}
+
+// ParseError describes a malformed line encountered while parsing.
+type ParseError struct {
+	Filename string // name of file being parsed; might be zero.
+	Line     int    // line number, starting with 1.
+	Text     string // text of the offending line.
+	Reason   string // why the line is malformed.
+}
+
+// Error returns a textual description of the malformed line, prefixed by the
+// file name and line number in the form of "file:3:", or just the line number
+// in the form of "line 3:" if the file name is unknown.
+func (e *ParseError) Error() string {
+	if e.Filename == "" {
+		return fmt.Sprintf("line %d: %s: %q", e.Line, e.Reason, e.Text)
+	}
+	return fmt.Sprintf("%s:%d: %s: %q", e.Filename, e.Line, e.Reason, e.Text)
+}
+
+// ParseErrors lists all malformed lines encountered while parsing in
+// ParseCollectAll mode.
+type ParseErrors []*ParseError
+
+// Error returns the textual descriptions of all malformed lines, one per line.
+func (e ParseErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the individual ParseError(s), for use with errors.Is and
+// errors.As.
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// lineParser keeps track of the current line number as well as the malformed
+// lines encountered so far, applying the configured ParseMode.
+type lineParser struct {
+	opts ParseOptions
+	line int
+	errs ParseErrors
+}
+
+// next advances to the next line.
+func (p *lineParser) next() {
+	p.line++
+}
+
+// malformed reports the current line to be malformed, returning a non-nil
+// error if parsing needs to be aborted. The abort flag tells whether
+// ParseDefault aborts on this particular kind of malformation.
+func (p *lineParser) malformed(text string, reason string, abort bool) error {
+	err := &ParseError{
+		Filename: p.opts.Filename,
+		Line:     p.line,
+		Text:     text,
+		Reason:   reason,
+	}
+	switch p.opts.Mode {
+	case ParseDefault:
+		if abort {
+			return err
+		}
+	case ParseStrict:
+		return err
+	case ParseCollectAll:
+		p.errs = append(p.errs, err)
+	}
+	return nil
+}
+
+// err returns the collected malformed lines as a ParseErrors error, or nil if
+// there were none.
+func (p *lineParser) err() error {
+	if len(p.errs) == 0 {
+		return nil
+	}
+	return p.errs
+}
+
+// splitComment splits a line into its definition and its trailing "#"
+// comment, with the comment text stripped of any surrounding whitespace.
+func splitComment(line string) (definition string, comment string) {
+	definition, comment, _ = strings.Cut(line, "#")
+	return definition, strings.TrimSpace(comment)
+}
@@ -0,0 +1,209 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("parse options", func() {
+
+	It("describes parse errors", func() {
+		Expect((&ParseError{
+			Filename: "/etc/services",
+			Line:     42,
+			Text:     "foo bar",
+			Reason:   "invalid port/protocol",
+		}).Error()).To(Equal(`/etc/services:42: invalid port/protocol: "foo bar"`))
+		Expect((&ParseError{
+			Line:   42,
+			Text:   "foo bar",
+			Reason: "invalid port/protocol",
+		}).Error()).To(Equal(`line 42: invalid port/protocol: "foo bar"`))
+
+		errs := ParseErrors{
+			{Line: 1, Text: "foo", Reason: "bad"},
+			{Line: 2, Text: "bar", Reason: "worse"},
+		}
+		Expect(errs.Error()).To(Equal("line 1: bad: \"foo\"\nline 2: worse: \"bar\""))
+		var perr *ParseError
+		Expect(errors.As(errs, &perr)).To(BeTrue())
+		Expect(perr.Line).To(Equal(1))
+	})
+
+	Context("services", func() {
+
+		var protos ProtocolIndex
+
+		BeforeEach(func() {
+			p, err := ParseProtocols(strings.NewReader(`foobar 12`))
+			Expect(err).NotTo(HaveOccurred())
+			protos = NewProtocolIndex(p)
+		})
+
+		const services = `# services
+crash 666/foobar
+crash
+crash 666
+crash 66x/foobar
+crash 666/baz
+`
+
+		It("parses leniently", func() {
+			s, err := ParseServicesWithOptions(strings.NewReader(services), protos,
+				ParseOptions{Mode: ParseLenient})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(HaveLen(1))
+		})
+
+		It("parses strictly", func() {
+			s, err := ParseServicesWithOptions(strings.NewReader(services), protos,
+				ParseOptions{Mode: ParseStrict, Filename: "services"})
+			Expect(err).To(MatchError(&ParseError{
+				Filename: "services",
+				Line:     3,
+				Text:     "crash",
+				Reason:   "missing port/protocol",
+			}))
+			Expect(s).To(BeNil())
+		})
+
+		It("collects all errors", func() {
+			s, err := ParseServicesWithOptions(strings.NewReader(services), protos,
+				ParseOptions{Mode: ParseCollectAll})
+			Expect(s).To(HaveLen(1))
+			Expect(err).To(BeAssignableToTypeOf(ParseErrors{}))
+			Expect(err.(ParseErrors)).To(HaveExactElements(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Line": Equal(3), "Reason": Equal("missing port/protocol")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Line": Equal(4), "Reason": Equal("invalid port/protocol")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Line": Equal(5), "Reason": Equal("invalid port number")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Line": Equal(6), "Reason": Equal("unknown protocol")})),
+			))
+		})
+
+		It("keeps services with unknown protocols", func() {
+			s, err := ParseServicesWithOptions(strings.NewReader(services), protos,
+				ParseOptions{KeepUnknownProtocols: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(HaveExactElements(
+				MatchFields(IgnoreExtras, Fields{"ProtocolName": Equal("foobar"), "Protocol": Not(BeNil())}),
+				MatchFields(IgnoreExtras, Fields{"ProtocolName": Equal("baz"), "Protocol": BeNil()}),
+			))
+		})
+
+		It("loads with options", func() {
+			_, err := LoadServicesWithOptions("test/non-existing-services", protos, ParseOptions{})
+			Expect(err).To(HaveOccurred())
+
+			idx, err := LoadServicesWithOptions("test/services-malformed", protos,
+				ParseOptions{Mode: ParseCollectAll})
+			Expect(err).To(MatchError(ContainSubstring("test/services-malformed:3: invalid port number")))
+			Expect(idx.ByPort(666, "foobar")).NotTo(BeNil())
+
+			idx, err = LoadServicesWithOptions("test/services-malformed", protos,
+				ParseOptions{Mode: ParseStrict})
+			Expect(err).To(HaveOccurred())
+			Expect(idx.Names).To(BeEmpty())
+		})
+
+	})
+
+	Context("protocols", func() {
+
+		const protocols = `# protocols
+foobar 66
+foobar
+foobar 66x
+barfoo 666
+`
+
+		It("parses leniently", func() {
+			p, err := ParseProtocolsWithOptions(strings.NewReader(protocols),
+				ParseOptions{Mode: ParseLenient})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(HaveLen(1))
+		})
+
+		It("parses strictly", func() {
+			p, err := ParseProtocolsWithOptions(strings.NewReader(protocols),
+				ParseOptions{Mode: ParseStrict})
+			Expect(err).To(MatchError(ContainSubstring("line 3: missing protocol number")))
+			Expect(p).To(BeNil())
+		})
+
+		It("collects all errors", func() {
+			p, err := ParseProtocolsWithOptions(strings.NewReader(protocols),
+				ParseOptions{Mode: ParseCollectAll})
+			Expect(p).To(HaveLen(1))
+			Expect(err).To(MatchError(ContainSubstring("line 4: invalid protocol number")))
+		})
+
+		It("loads with options", func() {
+			_, err := LoadProtocolsWithOptions("test/non-existing-protocols", ParseOptions{})
+			Expect(err).To(HaveOccurred())
+
+			idx, err := LoadProtocolsWithOptions("test/protocols", ParseOptions{Mode: ParseStrict})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(idx.Names).To(HaveKey("ratzfatz"))
+		})
+
+	})
+
+	Context("EtherTypes", func() {
+
+		const ethertypes = `# ethertypes
+foobar 66
+foobar # barfoo
+foobar 66x
+`
+
+		It("parses leniently", func() {
+			e, err := ParseEtherTypesWithOptions(strings.NewReader(ethertypes),
+				ParseOptions{Mode: ParseLenient})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(e).To(HaveLen(1))
+		})
+
+		It("parses strictly", func() {
+			e, err := ParseEtherTypesWithOptions(strings.NewReader(ethertypes),
+				ParseOptions{Mode: ParseStrict})
+			Expect(err).To(MatchError(ContainSubstring("line 3: missing EtherType number")))
+			Expect(e).To(BeNil())
+		})
+
+		It("collects all errors", func() {
+			e, err := ParseEtherTypesWithOptions(strings.NewReader(ethertypes),
+				ParseOptions{Mode: ParseCollectAll})
+			Expect(e).To(HaveLen(1))
+			Expect(err).To(MatchError(ContainSubstring("line 4: invalid EtherType number")))
+		})
+
+		It("loads with options", func() {
+			_, err := LoadEtherTypesWithOptions("test/non-existing-ethertypes", ParseOptions{})
+			Expect(err).To(HaveOccurred())
+
+			idx, err := LoadEtherTypesWithOptions("test/ethertypes", ParseOptions{Mode: ParseStrict})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(idx.Names).To(HaveKey("test"))
+		})
+
+	})
+
+})
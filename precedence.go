@@ -0,0 +1,60 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+// Precedence is the rule for picking the primary entry of an index key when
+// multiple entries share the same key, such as protocol number 0 being both
+// "ip" and "hopopt". The primary entry is what the ByName, ByNumber, ByPort,
+// et cetera lookups return, whereas the AllByName, AllByNumber, AllByPort, et
+// cetera lookups return all entries.
+type Precedence int
+
+const (
+	// DefaultPrecedence keeps the historic rules: the last merged entry wins,
+	// except for the transport-agnostic service keys (with a zero protocol
+	// name), where the first merged service wins. When merging another index,
+	// its entries always win. Host indexes follow glibc instead, where the
+	// first entry always wins.
+	DefaultPrecedence Precedence = iota
+	// FirstWins keeps the first merged entry for any key, including when
+	// merging another index.
+	FirstWins
+	// LastWins replaces existing entries with the last merged entry for any
+	// key, including the transport-agnostic service keys.
+	LastWins
+)
+
+// firstWins returns true if the first entry for a key wins, with the specified
+// default rule applying to DefaultPrecedence.
+func (p Precedence) firstWins(byDefault bool) bool {
+	switch p {
+	case FirstWins:
+		return true
+	case LastWins:
+		return false
+	}
+	return byDefault
+}
+
+// setPrimary sets the entry for the specified key, unless an entry for this
+// key already exists and the first entry wins.
+func setPrimary[K comparable, E any](m map[K]*E, key K, entry *E, firstWins bool) {
+	if firstWins {
+		if _, ok := m[key]; ok {
+			return
+		}
+	}
+	m[key] = entry
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("precedence", func() {
+
+	protos := func() []Protocol {
+		return []Protocol{
+			{Name: "ip", Number: 0, Aliases: []string{"IP"}},
+			{Name: "hopopt", Number: 0, Aliases: []string{"HOPOPT"}},
+		}
+	}
+
+	It("keeps the historic rules by default", func() {
+		pi := ProtocolIndex{}
+		pi.Merge(protos())
+		Expect(pi.ByNumber(0)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal("hopopt"),
+		})))
+
+		si := NewServiceIndex(nil)
+		si.Merge([]Service{
+			{Name: "foo", Port: 42, ProtocolName: "tcp"},
+			{Name: "bar", Port: 42, ProtocolName: "udp"},
+		})
+		Expect(si.ByPort(42, "")).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Name": Equal("foo"),
+		})))
+		Expect(si.ByPort(42, "udp").Name).To(Equal("bar"))
+	})
+
+	It("lets the first entry win", func() {
+		pi := ProtocolIndex{Precedence: FirstWins}
+		pi.Merge(protos())
+		Expect(pi.ByNumber(0).Name).To(Equal("ip"))
+
+		other := ProtocolIndex{}
+		other.Merge([]Protocol{{Name: "ipv0", Number: 0}})
+		pi.MergeIndex(other)
+		Expect(pi.ByNumber(0).Name).To(Equal("ip"))
+		Expect(pi.ByName("ipv0").Name).To(Equal("ipv0"))
+
+		ei := EtherTypeIndex{Precedence: FirstWins}
+		ei.Merge([]EtherType{
+			{Name: "IPv4", Number: 0x0800},
+			{Name: "IP", Number: 0x0800},
+		})
+		Expect(ei.ByNumber(0x0800).Name).To(Equal("IPv4"))
+	})
+
+	It("lets the last entry win", func() {
+		si := ServiceIndex{Precedence: LastWins}
+		si.Merge([]Service{
+			{Name: "foo", Port: 42, ProtocolName: "tcp"},
+			{Name: "bar", Port: 42, ProtocolName: "udp"},
+		})
+		Expect(si.ByPort(42, "").Name).To(Equal("bar"))
+		Expect(si.ByPort(42, "tcp").Name).To(Equal("foo"))
+	})
+
+	It("returns all entries", func() {
+		pi := ProtocolIndex{}
+		pi.Merge(protos())
+		Expect(pi.AllByNumber(0)).To(HaveExactElements(
+			HaveField("Name", "ip"),
+			HaveField("Name", "hopopt"),
+		))
+		Expect(pi.AllByName("HOPOPT")).To(HaveExactElements(HaveField("Name", "hopopt")))
+		Expect(pi.AllByNumber(1)).To(BeEmpty())
+
+		ei := EtherTypeIndex{}
+		ei.Merge([]EtherType{
+			{Name: "IPv4", Number: 0x0800},
+			{Name: "IP", Number: 0x0800, Aliases: []string{"IPv4"}},
+		})
+		Expect(ei.AllByNumber(0x0800)).To(HaveLen(2))
+		Expect(ei.AllByName("IPv4")).To(HaveExactElements(
+			HaveField("Name", "IPv4"),
+			HaveField("Name", "IP"),
+		))
+
+		si := ServiceIndex{}
+		si.Merge([]Service{
+			{Name: "foo", Port: 42, ProtocolName: "tcp"},
+			{Name: "bar", Port: 42, ProtocolName: "udp"},
+			{Name: "x11", Port: 40, LastPort: 50, ProtocolName: "tcp"},
+			{Name: "foo", Port: 666, ProtocolName: "udp"},
+		})
+		Expect(si.AllByPort(42, "")).To(HaveExactElements(
+			HaveField("Name", "foo"),
+			HaveField("Name", "bar"),
+			HaveField("Name", "x11"),
+		))
+		Expect(si.AllByPort(42, "udp")).To(HaveExactElements(HaveField("Name", "bar")))
+		Expect(si.AllByName("foo", "")).To(HaveExactElements(
+			HaveField("Port", 42),
+			HaveField("Port", 666),
+		))
+	})
+
+	It("returns all entries from the default indices", func() {
+		Expect(AllProtocolsByNumber(6)).To(HaveExactElements(HaveField("Name", "tcp")))
+		Expect(AllProtocolsByName("TCP")).To(HaveLen(1))
+		Expect(AllEtherTypesByNumber(0x0800)).NotTo(BeEmpty())
+		Expect(AllEtherTypesByName("IPv4")).NotTo(BeEmpty())
+		Expect(AllServicesByPort(22, "")).To(ContainElement(HaveField("Name", "ssh")))
+		Expect(AllServicesByName("ssh", "tcp")).To(HaveLen(1))
+	})
+
+})
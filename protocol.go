@@ -17,14 +17,19 @@ package netdb
 import (
 	"bufio"
 	"io"
+	"iter"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 )
 
 // Protocol describes a network communications protocol by its native name and
 // official protocol number as appearing within IP headers, with optional alias
-// names.
+// names and comment.
+//
+// To find out whether a protocol number denotes an IPv6 extension header in
+// contrast to an upper-layer protocol, use IsIPv6ExtensionHeader.
 //
 // According to
 // http://www.iana.org/assignments/protocol-numbers/protocol-numbers.xhtml the
@@ -37,13 +42,21 @@ type Protocol struct {
 	Name    string   // Official protocol name.
 	Number  uint8    // Protocol number.
 	Aliases []string // List of aliases.
+	Comment string   // Entry comment, if present.
 }
 
 // ProtocolIndex indexes the known network communication protocols by either
 // name (native as well as aliases) and by number.
+//
+// When multiple protocols share the same name or number, the Precedence rule
+// decides which protocol becomes the primary one. The Precedence rule needs to
+// be set before merging any protocols.
 type ProtocolIndex struct {
-	Names   map[string]*Protocol // Index by protocol name, including aliases.
-	Numbers map[uint8]*Protocol  // Index by protocol number.
+	Names      map[string]*Protocol // Index by protocol name, including aliases.
+	Numbers    map[uint8]*Protocol  // Index by protocol number.
+	Precedence Precedence           // Rule for picking the primary protocol.
+
+	entries []*Protocol // all merged protocols in definition order.
 }
 
 // NewProtocolIndex returns a ProtocolsIndex object initialized with the
@@ -60,54 +73,148 @@ func NewProtocolIndex(protos []Protocol) ProtocolIndex {
 // LoadProtocols returns a ProtocolIndex object initialized from the definitions
 // in the named file.
 func LoadProtocols(name string) (ProtocolIndex, error) {
+	return LoadProtocolsWithOptions(name, ParseOptions{})
+}
+
+// LoadProtocolsWithOptions returns a ProtocolIndex object initialized from the
+// definitions in the named file, parsing it as specified by the options. If
+// the options don't specify a file name, then the specified name is used. In
+// ParseCollectAll mode, the returned index contains all well-formed
+// definitions, even when a ParseErrors error is returned.
+func LoadProtocolsWithOptions(name string, opts ParseOptions) (ProtocolIndex, error) {
 	f, err := os.Open(name)
 	if err != nil {
 		return NewProtocolIndex(nil), err
 	}
 	defer f.Close()
-	protos, err := ParseProtocols(f)
-	if err != nil {
+	if opts.Filename == "" {
+		opts.Filename = name
+	}
+	protos, err := ParseProtocolsWithOptions(f, opts)
+	if protos == nil {
 		return NewProtocolIndex(nil), err
 	}
-	return NewProtocolIndex(protos), nil
+	return NewProtocolIndex(protos), err
 }
 
 // Merge a list of Protocol descriptions into the current Protocols index,
-// potentially overriding existing entries in the index in case of duplicates.
+// potentially overriding existing entries in the index in case of duplicates,
+// depending on the index's Precedence rule.
 func (i *ProtocolIndex) Merge(protos []Protocol) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
 	for idx, proto := range protos {
 		// index by name, including aliases
-		i.Names[proto.Name] = &protos[idx] // NEVER (re)use &proto! *facepalm*
+		setPrimary(i.Names, proto.Name, &protos[idx], firstWins) // NEVER (re)use &proto! *facepalm*
 		for _, alias := range proto.Aliases {
-			i.Names[alias] = &protos[idx]
+			setPrimary(i.Names, alias, &protos[idx], firstWins)
 		}
 		// index by protocol number
-		i.Numbers[proto.Number] = &protos[idx]
+		setPrimary(i.Numbers, proto.Number, &protos[idx], firstWins)
+		i.entries = append(i.entries, &protos[idx])
 	}
 }
 
 // MergeIndex merges another ProtocolIndex into the current index, potentially
-// overriding existing entries in case of duplicates.
+// overriding existing entries in case of duplicates, depending on the index's
+// Precedence rule.
 func (i *ProtocolIndex) MergeIndex(pi ProtocolIndex) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
 	for name, proto := range pi.Names {
-		i.Names[name] = proto
+		setPrimary(i.Names, name, proto, firstWins)
 	}
 	for number, proto := range pi.Numbers {
-		i.Numbers[number] = proto
+		setPrimary(i.Numbers, number, proto, firstWins)
+	}
+	i.entries = append(i.entries, pi.entries...)
+}
+
+// init initializes the index maps, if not already done.
+func (i *ProtocolIndex) init() {
+	if i.Names == nil {
+		i.Names = map[string]*Protocol{}
+	}
+	if i.Numbers == nil {
+		i.Numbers = map[uint8]*Protocol{}
 	}
 }
 
+// ByName returns the Protocol for the specified (alias) name, or nil if not
+// found.
+func (i *ProtocolIndex) ByName(name string) *Protocol {
+	return i.Names[name]
+}
+
+// ByNumber returns the Protocol for the specified protocol number, or nil if
+// not found.
+func (i *ProtocolIndex) ByNumber(number uint8) *Protocol {
+	return i.Numbers[number]
+}
+
+// AllByName returns all protocols with the specified (alias) name in the order
+// of their original definitions, including protocols overridden by later
+// merges.
+func (i *ProtocolIndex) AllByName(name string) []*Protocol {
+	var protos []*Protocol
+	for proto := range i.All() {
+		if proto.Name == name || slices.Contains(proto.Aliases, name) {
+			protos = append(protos, proto)
+		}
+	}
+	return protos
+}
+
+// AllByNumber returns all protocols with the specified protocol number in the
+// order of their original definitions, including protocols overridden by later
+// merges.
+func (i *ProtocolIndex) AllByNumber(number uint8) []*Protocol {
+	var protos []*Protocol
+	for proto := range i.All() {
+		if proto.Number == number {
+			protos = append(protos, proto)
+		}
+	}
+	return protos
+}
+
+// All returns an iterator over all protocols merged into this index, in the
+// order of their original definitions. Each Protocol is yielded only once,
+// regardless of its aliases, and including protocols that have been overridden
+// by later merges.
+func (i *ProtocolIndex) All() iter.Seq[*Protocol] {
+	return allOf(i.entries)
+}
+
 // ParseProtocols parses Internet protocol definitions for the TCP/IP subsystem
-// from the given Reader and returns them as a list of Protcol(s).
+// from the given Reader and returns them as a list of Protcol(s). Incomplete
+// definitions are silently skipped, while invalid protocol numbers result in
+// an error.
 func ParseProtocols(r io.Reader) ([]Protocol, error) {
+	return ParseProtocolsWithOptions(r, ParseOptions{})
+}
+
+// ParseProtocolsWithOptions parses Internet protocol definitions for the
+// TCP/IP subsystem from the given Reader as specified by the options and
+// returns them as a list of Protocol(s).
+func ParseProtocolsWithOptions(r io.Reader, opts ParseOptions) ([]Protocol, error) {
 	protos := []Protocol{}
+	lp := lineParser{opts: opts}
 
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
+		lp.next()
 		line := strings.TrimSpace(scanner.Text())
-		fields := strings.Fields(strings.SplitN(line, "#", 2)[0]) // There's always an element [0]
+		definition, comment := splitComment(line)
+		fields := strings.Fields(definition)
+		if len(fields) == 0 {
+			continue // skip empty lines and comment lines.
+		}
 		if len(fields) < 2 {
-			continue // skip empty lines and also silently ignore malformed lines.
+			if err := lp.malformed(line, "missing protocol number", false); err != nil {
+				return nil, err
+			}
+			continue
 		}
 
 		// Debian maintainers now had the glorious idea to put pseudo protocols
@@ -118,7 +225,10 @@ func ParseProtocols(r io.Reader) ([]Protocol, error) {
 		// another group of totally stable geniuses at work.
 		proto, err := strconv.ParseUint(fields[1], 10, 16)
 		if err != nil {
-			return nil, err
+			if err := lp.malformed(line, "invalid protocol number", true); err != nil {
+				return nil, err
+			}
+			continue
 		}
 		if proto > 255 {
 			continue
@@ -128,34 +238,69 @@ func ParseProtocols(r io.Reader) ([]Protocol, error) {
 			Name:    fields[0],
 			Number:  uint8(proto), // note that we already checked in ParseUint(..., 8)
 			Aliases: fields[2:],
+			Comment: comment,
 		})
 	}
 	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
 
-	return protos, nil
+	return protos, lp.err()
 }
 
 // ProtocolByName returns the Protocol details for the specified (alias) name,
 // or nil if not defined.
 func ProtocolByName(name string) *Protocol {
-	if Protocols.Numbers == nil {
-		Protocols = NewProtocolIndex(BuiltinProtocols)
-	}
-	return Protocols.Names[name]
+	idx := defaultProtocols.rlock()
+	defer defaultProtocols.runlock()
+	return idx.ByName(name)
 }
 
 // ProtocolByNumber returns the Protocol details for the specified protocol
 // number, or nil if not defined.
 func ProtocolByNumber(number uint8) *Protocol {
-	if Protocols.Numbers == nil {
-		Protocols = NewProtocolIndex(BuiltinProtocols)
-	}
-	return Protocols.Numbers[number]
+	idx := defaultProtocols.rlock()
+	defer defaultProtocols.runlock()
+	return idx.ByNumber(number)
+}
+
+// AllProtocolsByName returns all Protocol details for the specified (alias)
+// name, in the order of their definitions.
+func AllProtocolsByName(name string) []*Protocol {
+	idx := defaultProtocols.rlock()
+	defer defaultProtocols.runlock()
+	return idx.AllByName(name)
+}
+
+// AllProtocolsByNumber returns all Protocol details for the specified protocol
+// number, in the order of their definitions.
+func AllProtocolsByNumber(number uint8) []*Protocol {
+	idx := defaultProtocols.rlock()
+	defer defaultProtocols.runlock()
+	return idx.AllByNumber(number)
+}
+
+// AllProtocols returns an iterator over all protocols in the Protocols index, in
+// the order of their original definitions.
+func AllProtocols() iter.Seq[*Protocol] {
+	idx := defaultProtocols.rlock()
+	defer defaultProtocols.runlock()
+	return idx.All()
+}
+
+// SetProtocols atomically replaces the Protocols index with the specified
+// index. It is safe to call SetProtocols while lookups are in flight in other
+// goroutines; these lookups finish on the old index. Please note that Protocol
+// objects returned from the old index stay valid.
+func SetProtocols(i ProtocolIndex) {
+	defaultProtocols.set(i)
 }
 
 // Protocols is the index of protocol names and numbers. If left to the zero
 // value then it will be automatically initialized with the builtin definitions
-// upon first use of ProtocolByName or ProtocolByNumber.
+// upon first use of ProtocolByName, ProtocolByNumber, et cetera. This
+// initialization is goroutine-safe.
+//
+// Directly assigning to Protocols or merging into it is not safe while lookups
+// are in flight; use SetProtocols instead.
 var Protocols ProtocolIndex
@@ -16,6 +16,7 @@ package netdb
 
 import (
 	"os"
+	"slices"
 	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -58,6 +59,18 @@ foobar 66
 			Expect(p).To(HaveLen(1))
 		})
 
+		It("keeps trailing comments", func() {
+			p, err := ParseProtocols(strings.NewReader(`
+foobar 66 # foo bar
+ratzfatz	123 schwuppdiwupp
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(HaveExactElements(
+				HaveField("Comment", "foo bar"),
+				HaveField("Comment", ""),
+			))
+		})
+
 		It("silently skips malformed definitions", func() {
 			p, err := ParseProtocols(strings.NewReader(`
 foobar
@@ -125,6 +138,18 @@ ratzfatz	123 schwuppdiwupp siebenmeilenstiefler
 			Expect(idx.Numbers).To(HaveKey(uint8(123)))
 		})
 
+		It("iterates over all protocols in definition order", func() {
+			p, err := ParseProtocols(strings.NewReader(`
+ratzfatz	123 schwuppdiwupp siebenmeilenstiefler
+foobar	66
+barfoo	66
+`))
+			Expect(err).NotTo(HaveOccurred())
+			idx := NewProtocolIndex(p)
+			Expect(slices.Collect(idx.All())).To(HaveExactElements(
+				&p[0], &p[1], &p[2]))
+		})
+
 		It("merges indices", func() {
 			p, err := ParseProtocols(strings.NewReader(`
 ratzfatz	123 schwuppdiwupp siebenmeilenstiefler
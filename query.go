@@ -0,0 +1,161 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import "strings"
+
+// Query specifies how to look up services, protocols, and EtherTypes beyond
+// the exact matching done by ByName, ByPort, et cetera.
+//
+// The zero value of Query gives the same results as the exact lookups.
+type Query struct {
+	// IgnoreCase matches (alias) names case-insensitively, such as "HTTPS"
+	// matching "https". Exact matches always take precedence over
+	// case-insensitive matches, even when the exact match is for a less
+	// preferred protocol.
+	IgnoreCase bool
+	// Protocols lists the preferred protocol names of services in order of
+	// preference, such as "tcp", "sctp", "udp". If empty, any protocol
+	// matches, where the service defined first wins.
+	Protocols []string
+}
+
+// protocols returns the service protocol names to try in order.
+func (q Query) protocols() []string {
+	if len(q.Protocols) == 0 {
+		return []string{""}
+	}
+	return q.Protocols
+}
+
+// QueryByName returns the Service with the specified (alias) name, matching
+// and preferring protocols as specified by the query, or nil if not found.
+func (i *ServiceIndex) QueryByName(name string, q Query) *Service {
+	for _, protocol := range q.protocols() {
+		if service := i.ByName(name, protocol); service != nil {
+			return service
+		}
+	}
+	if !q.IgnoreCase {
+		return nil
+	}
+	for _, protocol := range q.protocols() {
+		for service := range i.All() {
+			if protocol != "" && service.ProtocolName != protocol {
+				continue
+			}
+			// Map the matching (alias) name back onto the index, so that we
+			// correctly take overridden services into account.
+			if matched, ok := foldMatch(name, service.Name, service.Aliases); ok {
+				if service := i.ByName(matched, protocol); service != nil {
+					return service
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// QueryByPort returns the Service for the specified port, preferring protocols
+// as specified by the query, or nil if not found.
+func (i *ServiceIndex) QueryByPort(port int, q Query) *Service {
+	for _, protocol := range q.protocols() {
+		if service := i.ByPort(port, protocol); service != nil {
+			return service
+		}
+	}
+	return nil
+}
+
+// QueryByName returns the Protocol with the specified (alias) name, matching as
+// specified by the query, or nil if not found. The protocol preferences of the
+// query are ignored.
+func (i *ProtocolIndex) QueryByName(name string, q Query) *Protocol {
+	if proto := i.Names[name]; proto != nil || !q.IgnoreCase {
+		return proto
+	}
+	for proto := range i.All() {
+		if matched, ok := foldMatch(name, proto.Name, proto.Aliases); ok {
+			if proto := i.Names[matched]; proto != nil {
+				return proto
+			}
+		}
+	}
+	return nil
+}
+
+// QueryByName returns the EtherType with the specified (alias) name, matching
+// as specified by the query, or nil if not found. The protocol preferences of
+// the query are ignored.
+func (i *EtherTypeIndex) QueryByName(name string, q Query) *EtherType {
+	if ethertype := i.Names[name]; ethertype != nil || !q.IgnoreCase {
+		return ethertype
+	}
+	for ethertype := range i.All() {
+		if matched, ok := foldMatch(name, ethertype.Name, ethertype.Aliases); ok {
+			if ethertype := i.Names[matched]; ethertype != nil {
+				return ethertype
+			}
+		}
+	}
+	return nil
+}
+
+// foldMatch returns the first of the native name and aliases that matches the
+// specified name case-insensitively, and true; otherwise, false.
+func foldMatch(name string, native string, aliases []string) (string, bool) {
+	if strings.EqualFold(name, native) {
+		return native, true
+	}
+	for _, alias := range aliases {
+		if strings.EqualFold(name, alias) {
+			return alias, true
+		}
+	}
+	return "", false
+}
+
+// QueryServiceByName returns the Service details for the specified (alias)
+// name, matching and preferring protocols as specified by the query, or nil if
+// not defined.
+func QueryServiceByName(name string, q Query) *Service {
+	idx := defaultServices.rlock()
+	defer defaultServices.runlock()
+	return idx.QueryByName(name, q)
+}
+
+// QueryServiceByPort returns the Service details for the specified port,
+// preferring protocols as specified by the query, or nil if not defined.
+func QueryServiceByPort(port int, q Query) *Service {
+	idx := defaultServices.rlock()
+	defer defaultServices.runlock()
+	return idx.QueryByPort(port, q)
+}
+
+// QueryProtocolByName returns the Protocol details for the specified (alias)
+// name, matching as specified by the query, or nil if not defined.
+func QueryProtocolByName(name string, q Query) *Protocol {
+	idx := defaultProtocols.rlock()
+	defer defaultProtocols.runlock()
+	return idx.QueryByName(name, q)
+}
+
+// QueryEtherTypeByName returns the EtherType details for the specified (alias)
+// name, matching as specified by the query, or nil if not defined.
+func QueryEtherTypeByName(name string, q Query) *EtherType {
+	idx := defaultEtherTypes.rlock()
+	defer defaultEtherTypes.runlock()
+	return idx.QueryByName(name, q)
+}
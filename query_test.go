@@ -0,0 +1,156 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("queries", func() {
+
+	Context("services", func() {
+
+		var idx ServiceIndex
+
+		BeforeEach(func() {
+			p, err := ParseProtocols(strings.NewReader(`
+tcp 6 TCP
+udp 17 UDP
+sctp 132 SCTP
+`))
+			Expect(err).NotTo(HaveOccurred())
+			s, err := ParseServices(strings.NewReader(`
+crash 666/udp Burn
+crash 666/sctp burn
+crash 666/tcp burn
+bang 42/udp
+`), NewProtocolIndex(p))
+			Expect(err).NotTo(HaveOccurred())
+			idx = NewServiceIndex(s)
+		})
+
+		It("matches exactly by default", func() {
+			Expect(idx.QueryByName("CRASH", Query{})).To(BeNil())
+			Expect(idx.QueryByName("crash", Query{})).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"ProtocolName": Equal("udp"),
+			})))
+			Expect(idx.QueryByName("Burn", Query{})).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"ProtocolName": Equal("udp"),
+			})))
+			Expect(idx.QueryByName("Burn", Query{Protocols: []string{"sctp"}})).To(BeNil())
+		})
+
+		It("matches case-insensitively", func() {
+			Expect(idx.QueryByName("CRASH", Query{IgnoreCase: true})).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"ProtocolName": Equal("udp"),
+			})))
+			Expect(idx.QueryByName("BURN", Query{
+				IgnoreCase: true,
+				Protocols:  []string{"sctp"},
+			})).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"ProtocolName": Equal("sctp"),
+			})))
+			Expect(idx.QueryByName("frotz", Query{IgnoreCase: true})).To(BeNil())
+		})
+
+		It("prefers exact matches over case-insensitive matches", func() {
+			Expect(idx.QueryByName("Burn", Query{
+				IgnoreCase: true,
+				Protocols:  []string{"tcp", "udp"},
+			})).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"ProtocolName": Equal("udp"),
+			})))
+		})
+
+		It("prefers protocols in order", func() {
+			q := Query{Protocols: []string{"tcp", "sctp", "udp"}}
+			Expect(idx.QueryByName("crash", q)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"ProtocolName": Equal("tcp"),
+			})))
+			Expect(idx.QueryByName("bang", q)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"ProtocolName": Equal("udp"),
+			})))
+			Expect(idx.QueryByName("bang", Query{Protocols: []string{"tcp"}})).To(BeNil())
+
+			Expect(idx.QueryByPort(666, q)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"ProtocolName": Equal("tcp"),
+			})))
+			Expect(idx.QueryByPort(42, q)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal("bang"),
+			})))
+			Expect(idx.QueryByPort(1, q)).To(BeNil())
+		})
+
+	})
+
+	Context("protocols and EtherTypes", func() {
+
+		It("matches protocols case-insensitively", func() {
+			p, err := ParseProtocols(strings.NewReader(`
+ratzfatz	123 schwuppdiwupp siebenmeilenstiefler
+`))
+			Expect(err).NotTo(HaveOccurred())
+			idx := NewProtocolIndex(p)
+			Expect(idx.QueryByName("RatzFatz", Query{})).To(BeNil())
+			Expect(idx.QueryByName("SchwuppdiWupp", Query{IgnoreCase: true})).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal("ratzfatz"),
+			})))
+			Expect(idx.QueryByName("frotz", Query{IgnoreCase: true})).To(BeNil())
+		})
+
+		It("matches EtherTypes case-insensitively", func() {
+			e, err := ParseEtherTypes(strings.NewReader(`
+RoMON	88BF	mikrotik-rommon mt-rommon		# MikroTik RoMON (unofficial)
+`))
+			Expect(err).NotTo(HaveOccurred())
+			idx := NewEtherTypeIndex(e)
+			Expect(idx.QueryByName("romon", Query{})).To(BeNil())
+			Expect(idx.QueryByName("romon", Query{IgnoreCase: true})).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Number": Equal(uint16(0x88bf)),
+			})))
+			Expect(idx.QueryByName("MT-ROMMON", Query{IgnoreCase: true})).NotTo(BeNil())
+			Expect(idx.QueryByName("frotz", Query{IgnoreCase: true})).To(BeNil())
+		})
+
+	})
+
+	Context("builtins", func() {
+
+		It("queries the builtin databases", func() {
+			Expect(QueryServiceByName("HTTPS", Query{
+				IgnoreCase: true,
+				Protocols:  []string{"tcp", "udp"},
+			})).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Port":         Equal(443),
+				"ProtocolName": Equal("tcp"),
+			})))
+			Expect(QueryServiceByPort(53, Query{Protocols: []string{"udp"}})).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Name": Equal("domain"),
+			})))
+			Expect(QueryProtocolByName("Udp", Query{IgnoreCase: true})).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Number": Equal(uint8(17)),
+			})))
+			Expect(QueryEtherTypeByName("ipv6", Query{IgnoreCase: true})).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Number": Equal(uint16(0x86dd)),
+			})))
+		})
+
+	})
+
+})
@@ -0,0 +1,268 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"bufio"
+	"io"
+	"iter"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RPCProgram describes an ONC RPC program, such as "nfs" or "mountd", by its
+// official name and program number, with optional alias names and comment, as
+// found in rpc(5).
+//
+// On purpose, we don't stick with the stuttering POSIX C library names, but
+// instead aim for more Go-like type names. After all, Go isn't similar to C,
+// except for using letters, signs, and braces.
+type RPCProgram struct {
+	Name    string   // Official RPC program name.
+	Number  uint32   // RPC program number.
+	Aliases []string // List of aliases.
+	Comment string   // Entry comment, if present.
+}
+
+// RPCProgramIndex indexes the known ONC RPC programs by either name (native as
+// well as aliases) and by program number.
+//
+// When multiple RPC programs share the same name or number, the Precedence
+// rule decides which RPC program becomes the primary one. The Precedence rule
+// needs to be set before merging any RPC programs.
+type RPCProgramIndex struct {
+	Names      map[string]*RPCProgram // Index by program name, including aliases.
+	Numbers    map[uint32]*RPCProgram // Index by program number.
+	Precedence Precedence             // Rule for picking the primary RPC program.
+
+	entries []*RPCProgram // all merged RPC programs in definition order.
+}
+
+// NewRPCProgramIndex returns an RPCProgramIndex object initialized with the
+// specified RPC programs.
+func NewRPCProgramIndex(programs []RPCProgram) RPCProgramIndex {
+	i := RPCProgramIndex{
+		Names:   map[string]*RPCProgram{},
+		Numbers: map[uint32]*RPCProgram{},
+	}
+	i.Merge(programs)
+	return i
+}
+
+// LoadRPC returns an RPCProgramIndex object initialized from the definitions
+// in the named file, such as "/etc/rpc".
+func LoadRPC(name string) (RPCProgramIndex, error) {
+	return LoadRPCWithOptions(name, ParseOptions{})
+}
+
+// LoadRPCWithOptions returns an RPCProgramIndex object initialized from the
+// definitions in the named file, parsing it as specified by the options. If
+// the options don't specify a file name, then the specified name is used. In
+// ParseCollectAll mode, the returned index contains all well-formed
+// definitions, even when a ParseErrors error is returned.
+func LoadRPCWithOptions(name string, opts ParseOptions) (RPCProgramIndex, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return NewRPCProgramIndex(nil), err
+	}
+	defer f.Close()
+	if opts.Filename == "" {
+		opts.Filename = name
+	}
+	programs, err := ParseRPCWithOptions(f, opts)
+	if programs == nil {
+		return NewRPCProgramIndex(nil), err
+	}
+	return NewRPCProgramIndex(programs), err
+}
+
+// Merge a list of RPCProgram descriptions into the current RPC programs index,
+// potentially overriding existing entries in the index in case of duplicates,
+// depending on the index's Precedence rule.
+func (i *RPCProgramIndex) Merge(programs []RPCProgram) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for idx, program := range programs {
+		setPrimary(i.Names, program.Name, &programs[idx], firstWins)
+		for _, alias := range program.Aliases {
+			setPrimary(i.Names, alias, &programs[idx], firstWins)
+		}
+		setPrimary(i.Numbers, program.Number, &programs[idx], firstWins)
+		i.entries = append(i.entries, &programs[idx])
+	}
+}
+
+// MergeIndex merges another RPCProgramIndex into the current index,
+// potentially overriding existing entries in case of duplicates, depending on
+// the index's Precedence rule.
+func (i *RPCProgramIndex) MergeIndex(rpi RPCProgramIndex) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
+	for name, program := range rpi.Names {
+		setPrimary(i.Names, name, program, firstWins)
+	}
+	for number, program := range rpi.Numbers {
+		setPrimary(i.Numbers, number, program, firstWins)
+	}
+	i.entries = append(i.entries, rpi.entries...)
+}
+
+// init initializes the index maps, if not already done.
+func (i *RPCProgramIndex) init() {
+	if i.Names == nil {
+		i.Names = map[string]*RPCProgram{}
+	}
+	if i.Numbers == nil {
+		i.Numbers = map[uint32]*RPCProgram{}
+	}
+}
+
+// ByName returns the RPCProgram for the specified (alias) name, or nil if not
+// found.
+func (i *RPCProgramIndex) ByName(name string) *RPCProgram {
+	return i.Names[name]
+}
+
+// ByNumber returns the RPCProgram for the specified program number, or nil if
+// not found.
+func (i *RPCProgramIndex) ByNumber(number uint32) *RPCProgram {
+	return i.Numbers[number]
+}
+
+// AllByName returns all RPC programs with the specified (alias) name in the
+// order of their original definitions, including RPC programs overridden by
+// later merges.
+func (i *RPCProgramIndex) AllByName(name string) []*RPCProgram {
+	var programs []*RPCProgram
+	for program := range i.All() {
+		if program.Name == name || slices.Contains(program.Aliases, name) {
+			programs = append(programs, program)
+		}
+	}
+	return programs
+}
+
+// AllByNumber returns all RPC programs with the specified program number in
+// the order of their original definitions, including RPC programs overridden
+// by later merges.
+func (i *RPCProgramIndex) AllByNumber(number uint32) []*RPCProgram {
+	var programs []*RPCProgram
+	for program := range i.All() {
+		if program.Number == number {
+			programs = append(programs, program)
+		}
+	}
+	return programs
+}
+
+// All returns an iterator over all RPC programs merged into this index, in the
+// order of their original definitions. Each RPC program is yielded only once,
+// regardless of its aliases, and including RPC programs that have been
+// overridden by later merges.
+func (i *RPCProgramIndex) All() iter.Seq[*RPCProgram] {
+	return allOf(i.entries)
+}
+
+// ParseRPC parses ONC RPC program definitions from the given Reader and
+// returns them as a list of RPCProgram(s). Incomplete definitions are silently
+// skipped, while invalid program numbers result in an error.
+func ParseRPC(r io.Reader) ([]RPCProgram, error) {
+	return ParseRPCWithOptions(r, ParseOptions{})
+}
+
+// ParseRPCWithOptions parses ONC RPC program definitions from the given Reader
+// as specified by the options and returns them as a list of RPCProgram(s).
+func ParseRPCWithOptions(r io.Reader, opts ParseOptions) ([]RPCProgram, error) {
+	programs := []RPCProgram{}
+	lp := lineParser{opts: opts}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lp.next()
+		line := strings.TrimSpace(scanner.Text())
+		definition, comment := splitComment(line)
+		fields := strings.Fields(definition)
+		if len(fields) == 0 {
+			continue // skip empty lines and comment lines.
+		}
+		if len(fields) < 2 {
+			if err := lp.malformed(line, "missing RPC program number", false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		number, err := strconv.ParseUint(fields[1], 10, 32)
+		if err != nil {
+			if err := lp.malformed(line, "invalid RPC program number", true); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		programs = append(programs, RPCProgram{
+			Name:    fields[0],
+			Number:  uint32(number),
+			Aliases: fields[2:],
+			Comment: comment,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return programs, lp.err()
+}
+
+// RPCProgramByName returns the RPCProgram details for the specified (native or
+// aliased) name, or nil if not defined.
+func RPCProgramByName(name string) *RPCProgram {
+	idx := defaultRPCPrograms.rlock()
+	defer defaultRPCPrograms.runlock()
+	return idx.ByName(name)
+}
+
+// RPCProgramByNumber returns the RPCProgram details for the specified program
+// number, or nil if not defined.
+func RPCProgramByNumber(number uint32) *RPCProgram {
+	idx := defaultRPCPrograms.rlock()
+	defer defaultRPCPrograms.runlock()
+	return idx.ByNumber(number)
+}
+
+// AllRPCPrograms returns an iterator over all RPC programs in the RPC programs
+// index, in the order of their original definitions.
+func AllRPCPrograms() iter.Seq[*RPCProgram] {
+	idx := defaultRPCPrograms.rlock()
+	defer defaultRPCPrograms.runlock()
+	return idx.All()
+}
+
+// SetRPCPrograms atomically replaces the RPC programs index with the specified
+// index. It is safe to call SetRPCPrograms while lookups are in flight in
+// other goroutines; these lookups finish on the old index. Please note that
+// RPCProgram objects returned from the old index stay valid.
+func SetRPCPrograms(i RPCProgramIndex) {
+	defaultRPCPrograms.set(i)
+}
+
+// RPCPrograms is the index of ONC RPC program names and numbers. If left to
+// the zero value, then it will be automatically initialized with the builtin
+// definitions upon first use of RPCProgramByName, RPCProgramByNumber, et
+// cetera. This initialization is goroutine-safe.
+//
+// Directly assigning to RPCPrograms or merging into it is not safe while
+// lookups are in flight; use SetRPCPrograms instead.
+var RPCPrograms RPCProgramIndex
@@ -0,0 +1,137 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("RPC programs", func() {
+
+	Context("parsing descriptions", func() {
+
+		It("returns correct descriptions", func() {
+			p, err := ParseRPC(strings.NewReader(`
+# A comment
+nfs		100003	nfsprog # network file system
+bwnfsd          788585389
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(HaveExactElements(
+				MatchFields(IgnoreExtras, Fields{
+					"Name":    Equal("nfs"),
+					"Number":  Equal(uint32(100003)),
+					"Aliases": ConsistOf("nfsprog"),
+					"Comment": Equal("network file system"),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Name":    Equal("bwnfsd"),
+					"Number":  Equal(uint32(788585389)),
+					"Aliases": BeEmpty(),
+					"Comment": BeEmpty(),
+				}),
+			))
+		})
+
+		It("skips incomplete definitions", func() {
+			p, err := ParseRPC(strings.NewReader("foo\nbar 100000\n"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(HaveExactElements(HaveField("Name", "bar")))
+		})
+
+		It("rejects invalid program numbers", func() {
+			_, err := ParseRPC(strings.NewReader("foo 4294967296\n"))
+			Expect(err).To(MatchError(ContainSubstring("invalid RPC program number")))
+			_, err = ParseRPC(strings.NewReader("foo bar\n"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("collects all errors", func() {
+			p, err := ParseRPCWithOptions(strings.NewReader("foo\nbar baz\nnfs 100003\n"),
+				ParseOptions{Mode: ParseCollectAll})
+			Expect(err).To(HaveLen(2))
+			Expect(p).To(HaveExactElements(HaveField("Name", "nfs")))
+		})
+
+	})
+
+	Context("loading", func() {
+
+		It("reports an error for a non-existing file", func() {
+			_, err := LoadRPC("test/non-existing-rpc")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("loads and indexes", func() {
+			idx, err := LoadRPC("test/rpc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(slices.Collect(idx.All())).To(HaveLen(3))
+			Expect(idx.ByName("rpcbind").Name).To(Equal("portmapper"))
+			Expect(idx.ByNumber(100005).Name).To(Equal("mountd"))
+			Expect(idx.ByNumber(42)).To(BeNil())
+			Expect(idx.ByName("nfs").Comment).To(Equal("network file system"))
+		})
+
+		It("merges", func() {
+			idx, err := LoadRPC("test/rpc")
+			Expect(err).NotTo(HaveOccurred())
+			idx.MergeIndex(NewRPCProgramIndex([]RPCProgram{
+				{Name: "nfs4", Number: 100003},
+			}))
+			Expect(idx.ByNumber(100003).Name).To(Equal("nfs4"))
+			Expect(idx.ByName("nfs").Name).To(Equal("nfs"))
+			Expect(idx.AllByNumber(100003)).To(HaveExactElements(
+				HaveField("Name", "nfs"),
+				HaveField("Name", "nfs4"),
+			))
+			Expect(idx.AllByName("mount")).To(HaveLen(1))
+		})
+
+	})
+
+	Context("package-level lookups", func() {
+
+		BeforeEach(func() {
+			RPCPrograms = RPCProgramIndex{}
+			DeferCleanup(func() {
+				RPCPrograms = RPCProgramIndex{}
+			})
+		})
+
+		It("looks up builtin RPC programs", func() {
+			Expect(RPCProgramByName("nlockmgr")).To(PointTo(MatchFields(IgnoreExtras, Fields{
+				"Number": Equal(uint32(100021)),
+			})))
+			Expect(RPCProgramByNumber(100005).Name).To(Equal("mountd"))
+			Expect(RPCProgramByName("portmap").Number).To(Equal(uint32(100000)))
+			Expect(slices.Collect(AllRPCPrograms())).To(HaveLen(len(BuiltinRPCPrograms)))
+		})
+
+		It("replaces the index", func() {
+			idx, err := LoadRPC("test/rpc")
+			Expect(err).NotTo(HaveOccurred())
+			SetRPCPrograms(idx)
+			Expect(RPCProgramByName("nlockmgr")).To(BeNil())
+			Expect(RPCProgramByName("nfs")).NotTo(BeNil())
+		})
+
+	})
+
+})
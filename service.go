@@ -16,31 +16,82 @@ package netdb
 
 import (
 	"bufio"
+	"cmp"
+	"errors"
 	"io"
+	"iter"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 )
 
 // Service describes a network service by its official service name, port number
-// and network protocol, with optional service alias names.
+// and network protocol, with optional service alias names. A Service might also
+// cover a range of ports, such as "x11 6000-6063/tcp".
 //
 // On purpose, we don't stick with the stuttering POSIX C library names, but
 // instead aim for more Go-like type names. After all, Go isn't similar to C,
 // except for using letters, signs, and braces.
 type Service struct {
 	Name         string    // Official service name.
-	Port         int       // Transport port number.
+	Port         int       // Transport port number; first port of a port range.
+	LastPort     int       // Last port of a port range; zero for a single port.
 	ProtocolName string    // Name of protocol to use.
 	Protocol     *Protocol // Protocol details, if known.
 	Aliases      []string  // List of service name aliases.
+	Comment      string    // Entry comment, if present.
+	Frequency    float64   // Open frequency, such as from nmap-services.
+
+	// Additional registration details, if known; such as when parsed from
+	// the IANA Service Name and Transport Protocol Port Number Registry.
+	Description string         // Service description.
+	Assignee    string         // Assignee(s) of the service name or port.
+	Contact     string         // Contact(s) for the service name or port.
+	Reference   string         // Reference(s), such as "[RFC959]".
+	Assignment  PortAssignment // Assignment status of the port.
+}
+
+// PortRange returns the first and last port number of the service. For a
+// single-port service, first and last are the same.
+func (s *Service) PortRange() (first, last int) {
+	if s.LastPort <= s.Port {
+		return s.Port, s.Port
+	}
+	return s.Port, s.LastPort
+}
+
+// HasPort reports whether the service covers the specified port number.
+func (s *Service) HasPort(port int) bool {
+	first, last := s.PortRange()
+	return port >= first && port <= last
 }
 
+// PortAssignment is the assignment status of a port as registered with IANA.
+// The zero value PortAssignmentUnknown applies to services not taken from the
+// IANA registry, such as services from /etc/services.
+type PortAssignment int
+
+const (
+	PortAssignmentUnknown PortAssignment = iota // assignment status is unknown.
+	PortAssigned                                // port has been assigned to a service.
+	PortReserved                                // port is reserved.
+	PortUnassigned                              // port is unassigned.
+)
+
 // ServiceIndex indexes the known network services by either (alias) name or by
 // transport port number.
+//
+// When multiple services share the same index key, the Precedence rule decides
+// which service becomes the primary one. The Precedence rule needs to be set
+// before merging any services.
 type ServiceIndex struct {
-	Names map[ServiceProtocol]*Service // Index by service name and protocol name.
-	Ports map[ServicePort]*Service     // Index by port number.
+	Names      map[ServiceProtocol]*Service // Index by service name and protocol name.
+	Ports      map[ServicePort]*Service     // Index by port number.
+	Precedence Precedence                   // Rule for picking the primary service.
+
+	entries []*Service // all merged services in definition order.
+	ranges  []*Service // all merged port range services in definition order.
 }
 
 // ServiceProtocol represents a Service index key.
@@ -69,55 +120,90 @@ func NewServiceIndex(services []Service) ServiceIndex {
 // LoadServices returns a ServiceIndex object initialized from the
 // definitions in the named file.
 func LoadServices(name string, protos ProtocolIndex) (ServiceIndex, error) {
+	return LoadServicesWithOptions(name, protos, ParseOptions{})
+}
+
+// LoadServicesWithOptions returns a ServiceIndex object initialized from the
+// definitions in the named file, parsing it as specified by the options. If
+// the options don't specify a file name, then the specified name is used. In
+// ParseCollectAll mode, the returned index contains all well-formed
+// definitions, even when a ParseErrors error is returned.
+func LoadServicesWithOptions(name string, protos ProtocolIndex, opts ParseOptions) (ServiceIndex, error) {
 	f, err := os.Open(name)
 	if err != nil {
 		return NewServiceIndex(nil), err
 	}
 	defer f.Close()
-	services, err := ParseServices(f, protos)
-	if err != nil {
+	if opts.Filename == "" {
+		opts.Filename = name
+	}
+	services, err := ParseServicesWithOptions(f, protos, opts)
+	if services == nil {
 		return NewServiceIndex(nil), err
 	}
-	return NewServiceIndex(services), nil
+	return NewServiceIndex(services), err
 }
 
 // Merge a list of service descriptions into the current Services index,
-// potentially overriding existing entries in the index in case of duplicates.
+// potentially overriding existing entries in the index in case of duplicates,
+// depending on the index's Precedence rule.
 func (i *ServiceIndex) Merge(services []Service) {
+	i.init()
+	// by default, only register first transport-agnostic instance of a
+	// service, but the last protocol-specific instance.
+	agnosticFirst := i.Precedence.firstWins(true)
+	specificFirst := i.Precedence.firstWins(false)
 	for idx, service := range services {
-		// only register first transport-agnostic instance of a service.
-		namekey := ServiceProtocol{Name: service.Name}
-		if _, ok := i.Names[namekey]; !ok {
-			i.Names[namekey] = &services[idx] // NEVER (re)use &service! *facepalm*
-		}
-		i.Names[ServiceProtocol{Name: service.Name, Protocol: service.ProtocolName}] = &services[idx]
+		entry := &services[idx] // NEVER (re)use &service! *facepalm*
+		setPrimary(i.Names, ServiceProtocol{Name: service.Name}, entry, agnosticFirst)
+		setPrimary(i.Names, ServiceProtocol{Name: service.Name, Protocol: service.ProtocolName}, entry, specificFirst)
 		for _, alias := range service.Aliases {
-			namekey := ServiceProtocol{Name: alias}
-			if _, ok := i.Names[namekey]; !ok {
-				i.Names[namekey] = &services[idx]
-			}
-			i.Names[ServiceProtocol{Name: alias, Protocol: service.ProtocolName}] = &services[idx]
+			setPrimary(i.Names, ServiceProtocol{Name: alias}, entry, agnosticFirst)
+			setPrimary(i.Names, ServiceProtocol{Name: alias, Protocol: service.ProtocolName}, entry, specificFirst)
 		}
-		// only register first transport-agnostic instance of a service.
-		portkey := ServicePort{Port: service.Port}
-		if _, ok := i.Ports[portkey]; !ok {
-			i.Ports[portkey] = &services[idx]
+		setPrimary(i.Ports, ServicePort{Port: service.Port}, entry, agnosticFirst)
+		setPrimary(i.Ports, ServicePort{Port: service.Port, Protocol: service.ProtocolName}, entry, specificFirst)
+		i.entries = append(i.entries, &services[idx])
+		if service.LastPort > service.Port {
+			i.ranges = append(i.ranges, &services[idx])
 		}
-		i.Ports[ServicePort{Port: service.Port, Protocol: service.ProtocolName}] = &services[idx]
 	}
 }
 
 // MergeIndex merges another ServiceIndex into the current index, potentially
-// overriding existing entries in case of duplicates.
+// overriding existing entries in case of duplicates, depending on the index's
+// Precedence rule.
 func (i *ServiceIndex) MergeIndex(si ServiceIndex) {
+	i.init()
+	firstWins := i.Precedence.firstWins(false)
 	for key, service := range si.Names {
-		i.Names[key] = service
+		setPrimary(i.Names, key, service, firstWins)
 	}
 	for key, service := range si.Ports {
-		i.Ports[key] = service
+		setPrimary(i.Ports, key, service, firstWins)
+	}
+	i.entries = append(i.entries, si.entries...)
+	i.ranges = append(i.ranges, si.ranges...)
+}
+
+// init initializes the index maps, if not already done.
+func (i *ServiceIndex) init() {
+	if i.Names == nil {
+		i.Names = map[ServiceProtocol]*Service{}
+	}
+	if i.Ports == nil {
+		i.Ports = map[ServicePort]*Service{}
 	}
 }
 
+// All returns an iterator over all services merged into this index, in the
+// order of their original definitions. Each Service is yielded only once,
+// regardless of its aliases, and including services that have been overridden
+// by later merges.
+func (i *ServiceIndex) All() iter.Seq[*Service] {
+	return allOf(i.entries)
+}
+
 // ByName returns the named Service for the given protocol, or nil if not found.
 // If the protocol is the zero value ("") then the "first" Service matching the
 // name is returned, where "first" refers to the order in which the services
@@ -131,72 +217,272 @@ func (i *ServiceIndex) ByName(name string, protocol string) *Service {
 // matching the name is returned, where "first" refers to the order in which the
 // services were originally described in a list of services, such as
 // /etc/services.
+//
+// Services for individual ports take precedence over port range services
+// covering the same port. For port ranges overlapping each other, the
+// Precedence rule of the index applies in the same way as for individual
+// ports.
 func (i *ServiceIndex) ByPort(port int, protocol string) *Service {
-	return i.Ports[ServicePort{Port: port, Protocol: protocol}]
+	if service := i.Ports[ServicePort{Port: port, Protocol: protocol}]; service != nil {
+		return service
+	}
+	firstWins := i.Precedence.firstWins(protocol == "")
+	var match *Service
+	for _, service := range i.ranges {
+		if !service.HasPort(port) || (protocol != "" && service.ProtocolName != protocol) {
+			continue
+		}
+		if firstWins {
+			return service
+		}
+		match = service
+	}
+	return match
+}
+
+// AllByName returns all services with the specified (alias) name and
+// protocol in the order of their original definitions, including services
+// overridden by later merges. If the protocol is the zero value ("") then
+// services with any protocol match.
+func (i *ServiceIndex) AllByName(name string, protocol string) []*Service {
+	var services []*Service
+	for service := range i.All() {
+		if protocol != "" && service.ProtocolName != protocol {
+			continue
+		}
+		if service.Name == name || slices.Contains(service.Aliases, name) {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+// AllByPort returns all services covering the specified port and protocol in
+// the order of their original definitions, including services overridden by
+// later merges. If the protocol is the zero value ("") then services with any
+// protocol match.
+func (i *ServiceIndex) AllByPort(port int, protocol string) []*Service {
+	var services []*Service
+	for service := range i.All() {
+		if protocol != "" && service.ProtocolName != protocol {
+			continue
+		}
+		if service.HasPort(port) {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+// RankedByPort returns all services covering the specified port and protocol,
+// ordered by their Frequency from most to least likely. Services with the same
+// frequency keep the order of their original definitions. If the protocol is
+// the zero value ("") then services with any protocol match.
+func (i *ServiceIndex) RankedByPort(port int, protocol string) []*Service {
+	services := i.AllByPort(port, protocol)
+	slices.SortStableFunc(services, func(a, b *Service) int {
+		return cmp.Compare(b.Frequency, a.Frequency)
+	})
+	return services
+}
+
+// ByPortRange returns the services with port numbers in the closed interval
+// [lo, hi] for the given protocol, sorted by port number. If the protocol is
+// the zero value ("") then the services for all protocols are returned, sorted
+// first by port number and then by protocol name. Port range services are
+// included when they overlap with [lo, hi]. Services sharing the same port and
+// protocol are all returned in the order of their original definitions,
+// including services overridden by later merges. If there are no services in
+// the specified range, ByPortRange returns nil.
+func (i *ServiceIndex) ByPortRange(lo, hi int, protocol string) []*Service {
+	var services []*Service
+	for service := range i.All() {
+		if protocol != "" && service.ProtocolName != protocol {
+			continue
+		}
+		first, last := service.PortRange()
+		if first <= hi && last >= lo {
+			services = append(services, service)
+		}
+	}
+	slices.SortStableFunc(services, func(a, b *Service) int {
+		if c := cmp.Compare(a.Port, b.Port); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ProtocolName, b.ProtocolName)
+	})
+	return services
 }
 
 // ParseServices parses network service definitions from the given Reader and
-// returns them as a list of Service(s).
+// returns them as a list of Service(s). Malformed definitions as well as
+// definitions with protocols not in the specified ProtocolIndex are silently
+// skipped.
 func ParseServices(r io.Reader, p ProtocolIndex) ([]Service, error) {
+	return ParseServicesWithOptions(r, p, ParseOptions{})
+}
+
+// ParseServicesWithOptions parses network service definitions from the given
+// Reader as specified by the options and returns them as a list of
+// Service(s).
+func ParseServicesWithOptions(r io.Reader, p ProtocolIndex, opts ParseOptions) ([]Service, error) {
 	services := []Service{}
+	lp := lineParser{opts: opts}
 
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
+		lp.next()
 		line := strings.TrimSpace(scanner.Text())
-		fields := strings.Fields(strings.SplitN(line, "#", 2)[0]) // There's always an element [0]
-		if len(fields) < 2 {
-			continue // skip empty lines and also silently ignore malformed lines.
+		definition, comment := splitComment(line)
+		fields := strings.Fields(definition)
+		if len(fields) == 0 {
+			continue // skip empty lines and comment lines.
 		}
-
-		portprotocol := strings.Split(fields[1], "/")
-		if len(portprotocol) != 2 {
+		if len(fields) < 2 {
+			if err := lp.malformed(line, "missing port/protocol", false); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
-		port, err := strconv.ParseUint(portprotocol[0], 10, 16)
+		port, lastport, protocol, err := parsePortProtocol(fields[1])
 		if err != nil {
+			if err := lp.malformed(line, err.Error(), false); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
-		proto, ok := p.Names[portprotocol[1]]
-		if !ok {
+		proto, ok := p.Names[protocol]
+		if !ok && !opts.KeepUnknownProtocols {
+			if err := lp.malformed(line, "unknown protocol", false); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
 		services = append(services, Service{
 			Name:         fields[0],
-			Port:         int(port),
-			ProtocolName: portprotocol[1],
+			Port:         port,
+			LastPort:     lastport,
+			ProtocolName: protocol,
 			Protocol:     proto,
 			Aliases:      fields[2:],
+			Comment:      comment,
 		})
 	}
 	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
 
-	return services, nil
+	return services, lp.err()
+}
+
+// parsePortProtocol parses a "port/protocol" field, where the port can also
+// be a port range in the form of "first-last". For a single port number, the
+// returned last port is zero.
+func parsePortProtocol(field string) (first, last int, protocol string, err error) {
+	ports, protocol, ok := strings.Cut(field, "/")
+	if !ok || strings.Contains(protocol, "/") {
+		return 0, 0, "", errors.New("invalid port/protocol")
+	}
+	first, last, err = parsePortRange(ports)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	return first, last, protocol, nil
+}
+
+// parsePortRange parses either a single port number or a port range in the
+// form of "first-last". For a single port number, the returned last port is
+// zero.
+func parsePortRange(ports string) (first, last int, err error) {
+	from, to, isRange := strings.Cut(ports, "-")
+	f, err := strconv.ParseUint(from, 10, 16)
+	if err != nil {
+		return 0, 0, errors.New("invalid port number")
+	}
+	if !isRange {
+		return int(f), 0, nil
+	}
+	l, err := strconv.ParseUint(to, 10, 16)
+	if err != nil || l <= f {
+		return 0, 0, errors.New("invalid port range")
+	}
+	return int(f), int(l), nil
 }
 
 // ServiceByName returns the Service details for the specified (alias) name and
 // (optional) protocol name, or nil if not defined.
 func ServiceByName(name string, protocol string) *Service {
-	if Services.Names == nil {
-		Services = NewServiceIndex(BuiltinServices)
-	}
-	return Services.ByName(name, protocol)
+	idx := defaultServices.rlock()
+	defer defaultServices.runlock()
+	return idx.ByName(name, protocol)
 }
 
 // ServiceByPort returns the Service details for the specified port number and
 // (optional) protocol name, or nil if not defined.
 func ServiceByPort(port int, protocol string) *Service {
-	if Services.Names == nil {
-		Services = NewServiceIndex(BuiltinServices)
-	}
-	return Services.ByPort(port, protocol)
+	idx := defaultServices.rlock()
+	defer defaultServices.runlock()
+	return idx.ByPort(port, protocol)
+}
+
+// ServiceByPortRange returns the Service details for the services with port
+// numbers in the closed interval [lo, hi] and (optional) protocol name, sorted
+// by port number.
+func ServiceByPortRange(lo, hi int, protocol string) []*Service {
+	idx := defaultServices.rlock()
+	defer defaultServices.runlock()
+	return idx.ByPortRange(lo, hi, protocol)
+}
+
+// AllServicesByName returns all Service details for the specified (alias) name
+// and (optional) protocol name, in the order of their definitions.
+func AllServicesByName(name string, protocol string) []*Service {
+	idx := defaultServices.rlock()
+	defer defaultServices.runlock()
+	return idx.AllByName(name, protocol)
+}
+
+// AllServicesByPort returns all Service details for the specified port number
+// and (optional) protocol name, in the order of their definitions.
+func AllServicesByPort(port int, protocol string) []*Service {
+	idx := defaultServices.rlock()
+	defer defaultServices.runlock()
+	return idx.AllByPort(port, protocol)
+}
+
+// RankedServicesByPort returns all Service details for the specified port
+// number and (optional) protocol name, ordered by their Frequency from most to
+// least likely.
+func RankedServicesByPort(port int, protocol string) []*Service {
+	idx := defaultServices.rlock()
+	defer defaultServices.runlock()
+	return idx.RankedByPort(port, protocol)
+}
+
+// AllServices returns an iterator over all services in the Services index, in
+// the order of their original definitions.
+func AllServices() iter.Seq[*Service] {
+	idx := defaultServices.rlock()
+	defer defaultServices.runlock()
+	return idx.All()
+}
+
+// SetServices atomically replaces the Services index with the specified index.
+// It is safe to call SetServices while lookups are in flight in other
+// goroutines; these lookups finish on the old index. Please note that Service
+// objects returned from the old index stay valid.
+func SetServices(i ServiceIndex) {
+	defaultServices.set(i)
 }
 
 // Services is the index of service names and protocols. If left to the zero
 // value then it will be automatically initialized with the builtin definitions
-// upon first use of ServiceByName or ServiceByPort.
+// upon first use of ServiceByName, ServiceByPort, et cetera. This
+// initialization is goroutine-safe.
+//
+// Directly assigning to Services or merging into it is not safe while lookups
+// are in flight; use SetServices instead.
 var Services ServiceIndex
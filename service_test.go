@@ -16,6 +16,7 @@ package netdb
 
 import (
 	"os"
+	"slices"
 	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -53,7 +54,8 @@ crash 666/baz burn
 						"Name":   Equal("foobar"),
 						"Number": Equal(uint8(12)),
 					})),
-					"Aliases": ConsistOf("burn"),
+					"Aliases":    ConsistOf("burn"),
+					"Assignment": Equal(PortAssignmentUnknown),
 				}),
 				MatchFields(IgnoreExtras, Fields{
 					"Name":         Equal("crash"),
@@ -78,6 +80,18 @@ crash 666/foobar burn # And this one.
 			Expect(s).To(HaveLen(1))
 		})
 
+		It("keeps trailing comments", func() {
+			s, err := ParseServices(strings.NewReader(`
+crash 666/foobar burn	# Crash and burn
+crash 666/baz #
+`), protos)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(HaveExactElements(
+				HaveField("Comment", "Crash and burn"),
+				HaveField("Comment", ""),
+			))
+		})
+
 		It("silently skips malformed definitions (including non-defined protocols)", func() {
 			s, err := ParseServices(strings.NewReader(`
 crash and burn
@@ -149,6 +163,101 @@ crash 666/baz burn
 			})))
 		})
 
+		It("returns services in port ranges", func() {
+			s, err := ParseServices(strings.NewReader(`
+gamma 668/foobar
+alpha 666/foobar
+beta 667/baz
+alpha 666/baz
+delta 700/foobar
+`), protos)
+			Expect(err).NotTo(HaveOccurred())
+			idx := NewServiceIndex(s)
+
+			Expect(idx.ByPortRange(600, 665, "")).To(BeEmpty())
+			Expect(idx.ByPortRange(666, 668, "foobar")).To(HaveExactElements(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Name": Equal("alpha")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Name": Equal("gamma")})),
+			))
+			Expect(idx.ByPortRange(666, 668, "")).To(HaveExactElements(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Name": Equal("alpha"), "ProtocolName": Equal("baz")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Name": Equal("alpha"), "ProtocolName": Equal("foobar")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Name": Equal("beta")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Name": Equal("gamma")})),
+			))
+		})
+
+		It("returns all services sharing a port in a port range", func() {
+			s, err := ParseServices(strings.NewReader(`
+alpha 666/foobar
+beta 666/foobar
+gamma 667/foobar
+`), protos)
+			Expect(err).NotTo(HaveOccurred())
+			idx := NewServiceIndex(s)
+			Expect(idx.ByPort(666, "foobar").Name).To(Equal("beta"))
+			Expect(idx.ByPortRange(666, 667, "foobar")).To(HaveExactElements(
+				BeIdenticalTo(&s[0]), BeIdenticalTo(&s[1]), BeIdenticalTo(&s[2])))
+		})
+
+		It("iterates over all services in definition order", func() {
+			s, err := ParseServices(strings.NewReader(`
+crash 666/foobar burn
+crash 666/baz burn
+bang 666/foobar
+`), protos)
+			Expect(err).NotTo(HaveOccurred())
+			idx := NewServiceIndex(s)
+			Expect(slices.Collect((&ServiceIndex{}).All())).To(BeEmpty())
+			Expect(slices.Collect(idx.All())).To(HaveExactElements(
+				&s[0], &s[1], &s[2]))
+
+			idx.MergeIndex(NewServiceIndex(s[:1]))
+			Expect(slices.Collect(idx.All())).To(HaveLen(3))
+		})
+
+		It("looks up port range services", func() {
+			s, err := ParseServices(strings.NewReader(`
+x11 6000-6063/foobar x
+x11 6000-6063/baz
+xx 6010-6019/foobar
+vnc 6001/foobar
+`), protos)
+			Expect(err).NotTo(HaveOccurred())
+			first, last := s[0].PortRange()
+			Expect([]int{first, last}).To(Equal([]int{6000, 6063}))
+			first, last = s[3].PortRange()
+			Expect([]int{first, last}).To(Equal([]int{6001, 6001}))
+			Expect(s[0].HasPort(5999)).To(BeFalse())
+			Expect(s[0].HasPort(6063)).To(BeTrue())
+			idx := NewServiceIndex(s)
+
+			Expect(idx.ByPort(6000, "baz")).To(BeIdenticalTo(&s[1]))
+			Expect(idx.ByPort(6001, "foobar")).To(BeIdenticalTo(&s[3]))
+			Expect(idx.ByPort(6002, "foobar")).To(BeIdenticalTo(&s[0]))
+			Expect(idx.ByPort(6002, "")).To(BeIdenticalTo(&s[0]))
+			Expect(idx.ByPort(6015, "foobar")).To(BeIdenticalTo(&s[2]))
+			Expect(idx.ByPort(6015, "")).To(BeIdenticalTo(&s[0]))
+			Expect(idx.ByPort(6063, "baz")).To(BeIdenticalTo(&s[1]))
+			Expect(idx.ByPort(6064, "")).To(BeNil())
+
+			Expect(idx.ByPortRange(6002, 6010, "foobar")).To(HaveExactElements(
+				BeIdenticalTo(&s[0]), BeIdenticalTo(&s[2])))
+			Expect(idx.ByPortRange(6002, 6009, "")).To(HaveExactElements(
+				BeIdenticalTo(&s[1]), BeIdenticalTo(&s[0])))
+		})
+
+		It("rejects invalid port ranges", func() {
+			_, err := ParseServicesWithOptions(strings.NewReader(`
+x11 6063-6000/foobar
+`), protos, ParseOptions{Mode: ParseStrict})
+			Expect(err).To(MatchError(ContainSubstring("invalid port range")))
+			_, err = ParseServicesWithOptions(strings.NewReader(`
+x11 6000-60x/foobar
+`), protos, ParseOptions{Mode: ParseStrict})
+			Expect(err).To(MatchError(ContainSubstring("invalid port range")))
+		})
+
 		It("merges indices", func() {
 			s, err := ParseServices(strings.NewReader(`
 crash 666/foobar
@@ -184,6 +293,15 @@ crash 666/baz
 			})))
 		})
 
+		It("looks services up by port range", func() {
+			Expect(ServiceByPortRange(20, 23, "tcp")).To(HaveExactElements(
+				PointTo(MatchFields(IgnoreExtras, Fields{"Name": Equal("ftp-data")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Name": Equal("ftp")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Name": Equal("ssh")})),
+				PointTo(MatchFields(IgnoreExtras, Fields{"Name": Equal("telnet")})),
+			))
+		})
+
 	})
 
 })
@@ -0,0 +1,194 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import "reflect"
+
+// ServiceSource looks up services by name or port, such as a ServiceIndex or
+// a ServiceChain. Both methods return nil if the source doesn't know the
+// service.
+type ServiceSource interface {
+	ByName(name string, protocol string) *Service
+	ByPort(port int, protocol string) *Service
+}
+
+// ProtocolSource looks up protocols by name or number, such as a ProtocolIndex
+// or a ProtocolChain. Both methods return nil if the source doesn't know the
+// protocol.
+type ProtocolSource interface {
+	ByName(name string) *Protocol
+	ByNumber(number uint8) *Protocol
+}
+
+// EtherTypeSource looks up EtherTypes by name or number, such as an
+// EtherTypeIndex or an EtherTypeChain. Both methods return nil if the source
+// doesn't know the EtherType.
+type EtherTypeSource interface {
+	ByName(name string) *EtherType
+	ByNumber(number uint16) *EtherType
+}
+
+// DefaultServiceSource looks up services in the package-level Services index,
+// which defaults to the builtin definitions.
+var DefaultServiceSource ServiceSource = defaultServiceSource{}
+
+// DefaultProtocolSource looks up protocols in the package-level Protocols
+// index, which defaults to the builtin definitions.
+var DefaultProtocolSource ProtocolSource = defaultProtocolSource{}
+
+// DefaultEtherTypeSource looks up EtherTypes in the package-level EtherTypes
+// index, which defaults to the builtin definitions.
+var DefaultEtherTypeSource EtherTypeSource = defaultEtherTypeSource{}
+
+type defaultServiceSource struct{}
+
+func (defaultServiceSource) ByName(name string, protocol string) *Service {
+	return ServiceByName(name, protocol)
+}
+
+func (defaultServiceSource) ByPort(port int, protocol string) *Service {
+	return ServiceByPort(port, protocol)
+}
+
+type defaultProtocolSource struct{}
+
+func (defaultProtocolSource) ByName(name string) *Protocol { return ProtocolByName(name) }
+
+func (defaultProtocolSource) ByNumber(number uint8) *Protocol { return ProtocolByNumber(number) }
+
+type defaultEtherTypeSource struct{}
+
+func (defaultEtherTypeSource) ByName(name string) *EtherType { return EtherTypeByName(name) }
+
+func (defaultEtherTypeSource) ByNumber(number uint16) *EtherType { return EtherTypeByNumber(number) }
+
+// Action tells a chain how to proceed after consulting one of its sources,
+// modeled after the actions in nsswitch.conf(5).
+type Action int
+
+const (
+	ActionDefault  Action = iota // default action for the particular status.
+	ActionReturn                 // return the current result.
+	ActionContinue               // consult the next source.
+)
+
+// Criteria control how a chain proceeds depending on the status of consulting
+// a source, modeled after the "[STATUS=ACTION]" criteria in nsswitch.conf(5).
+// The zero value applies the default nsswitch actions: return on success,
+// otherwise continue with the next source.
+type Criteria struct {
+	Success  Action // source found an entry; defaults to ActionReturn.
+	NotFound Action // source didn't find an entry; defaults to ActionContinue.
+	Unavail  Action // source is unavailable; defaults to ActionContinue.
+}
+
+// Link is a single source in a chain, together with its criteria. A nil
+// Source is considered to be unavailable; this includes interfaces holding a
+// nil pointer, such as a (*ServiceIndex)(nil).
+type Link[S any] struct {
+	Name     string // optional source name, such as "files".
+	Source   S
+	Criteria Criteria
+}
+
+// ServiceChain consults its service sources in order, where by default the
+// first hit wins. A ServiceChain is a ServiceSource itself.
+type ServiceChain []Link[ServiceSource]
+
+// ByName returns the Service for the specified (alias) name and (optional)
+// protocol name, or nil if not found.
+func (c ServiceChain) ByName(name string, protocol string) *Service {
+	return lookupChain(c, func(s ServiceSource) *Service { return s.ByName(name, protocol) })
+}
+
+// ByPort returns the Service for the specified port number and (optional)
+// protocol name, or nil if not found.
+func (c ServiceChain) ByPort(port int, protocol string) *Service {
+	return lookupChain(c, func(s ServiceSource) *Service { return s.ByPort(port, protocol) })
+}
+
+// ProtocolChain consults its protocol sources in order, where by default the
+// first hit wins. A ProtocolChain is a ProtocolSource itself.
+type ProtocolChain []Link[ProtocolSource]
+
+// ByName returns the Protocol for the specified (alias) name, or nil if not
+// found.
+func (c ProtocolChain) ByName(name string) *Protocol {
+	return lookupChain(c, func(s ProtocolSource) *Protocol { return s.ByName(name) })
+}
+
+// ByNumber returns the Protocol for the specified protocol number, or nil if
+// not found.
+func (c ProtocolChain) ByNumber(number uint8) *Protocol {
+	return lookupChain(c, func(s ProtocolSource) *Protocol { return s.ByNumber(number) })
+}
+
+// EtherTypeChain consults its EtherType sources in order, where by default the
+// first hit wins. An EtherTypeChain is an EtherTypeSource itself.
+type EtherTypeChain []Link[EtherTypeSource]
+
+// ByName returns the EtherType for the specified (alias) name, or nil if not
+// found.
+func (c EtherTypeChain) ByName(name string) *EtherType {
+	return lookupChain(c, func(s EtherTypeSource) *EtherType { return s.ByName(name) })
+}
+
+// ByNumber returns the EtherType for the specified EtherType number, or nil if
+// not found.
+func (c EtherTypeChain) ByNumber(number uint16) *EtherType {
+	return lookupChain(c, func(s EtherTypeSource) *EtherType { return s.ByNumber(number) })
+}
+
+// lookupChain consults the sources of a chain in order until the criteria of
+// a source tell to return. If the criteria tell to continue after a success,
+// the result of the last successful source is returned when the chain runs
+// out of sources. Returning because of a source being unavailable or not
+// finding an entry always returns nil, as does glibc.
+func lookupChain[S any, E any](links []Link[S], lookup func(S) *E) *E {
+	var result *E
+	for _, link := range links {
+		if isNilSource(link.Source) {
+			if link.Criteria.Unavail == ActionReturn {
+				return nil
+			}
+			continue
+		}
+		if entry := lookup(link.Source); entry != nil {
+			result = entry
+			if link.Criteria.Success != ActionContinue {
+				return result
+			}
+			continue
+		}
+		if link.Criteria.NotFound == ActionReturn {
+			return nil
+		}
+	}
+	return result
+}
+
+// isNilSource returns true if the specified source is nil, either as a nil
+// interface or as an interface holding a nil pointer, map, et cetera.
+func isNilSource(source any) bool {
+	if source == nil {
+		return true
+	}
+	v := reflect.ValueOf(source)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("lookup sources", func() {
+
+	var files ServiceIndex
+	var protos ProtocolIndex
+
+	BeforeEach(func() {
+		protos = NewProtocolIndex(BuiltinProtocols)
+		s, err := ParseServices(strings.NewReader(`
+domain 5353/udp
+crash 666/tcp
+`), protos)
+		Expect(err).NotTo(HaveOccurred())
+		files = NewServiceIndex(s)
+	})
+
+	It("consults sources in order with first hit winning", func() {
+		chain := ServiceChain{
+			{Name: "files", Source: &files},
+			{Source: nil},
+			{Name: "nilindex", Source: (*ServiceIndex)(nil)},
+			{Name: "nilchain", Source: ServiceChain(nil)},
+			{Name: "builtin", Source: DefaultServiceSource},
+		}
+		Expect(chain.ByName("domain", "udp")).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Port": Equal(5353),
+		})))
+		Expect(chain.ByName("domain", "tcp")).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Port": Equal(53),
+		})))
+		Expect(chain.ByPort(666, "tcp")).NotTo(BeNil())
+		Expect(chain.ByPort(22, "tcp")).NotTo(BeNil())
+		Expect(chain.ByPort(1, "frotz")).To(BeNil())
+	})
+
+	It("honors criteria", func() {
+		chain := ServiceChain{
+			{Source: &files, Criteria: Criteria{NotFound: ActionReturn}},
+			{Source: DefaultServiceSource},
+		}
+		Expect(chain.ByName("domain", "tcp")).To(BeNil())
+
+		chain = ServiceChain{
+			{Source: nil, Criteria: Criteria{Unavail: ActionReturn}},
+			{Source: DefaultServiceSource},
+		}
+		Expect(chain.ByPort(22, "tcp")).To(BeNil())
+
+		chain = ServiceChain{
+			{Source: (*ServiceIndex)(nil), Criteria: Criteria{Unavail: ActionReturn}},
+			{Source: DefaultServiceSource},
+		}
+		Expect(chain.ByPort(22, "tcp")).To(BeNil())
+
+		chain = ServiceChain{
+			{Source: DefaultServiceSource, Criteria: Criteria{Success: ActionContinue}},
+			{Source: &files},
+		}
+		Expect(chain.ByName("domain", "udp")).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Port": Equal(5353),
+		})))
+		Expect(chain.ByName("ssh", "tcp")).NotTo(BeNil())
+	})
+
+	It("chains protocols and EtherTypes", func() {
+		p, err := ParseProtocols(strings.NewReader("tcp 66\n"))
+		Expect(err).NotTo(HaveOccurred())
+		pidx := NewProtocolIndex(p)
+		pchain := ProtocolChain{{Source: &pidx}, {Source: DefaultProtocolSource}}
+		Expect(pchain.ByName("tcp").Number).To(Equal(uint8(66)))
+		Expect(pchain.ByNumber(17).Name).To(Equal("udp"))
+
+		e, err := ParseEtherTypes(strings.NewReader("IPv4 0801\n"))
+		Expect(err).NotTo(HaveOccurred())
+		eidx := NewEtherTypeIndex(e)
+		echain := EtherTypeChain{{Source: &eidx}, {Source: DefaultEtherTypeSource}}
+		Expect(echain.ByName("IPv4").Number).To(Equal(uint16(0x0801)))
+		Expect(echain.ByNumber(0x86dd).Name).To(Equal("IPv6"))
+	})
+
+	Context("nsswitch.conf", func() {
+
+		It("parses nsswitch configurations", func() {
+			nss, err := ParseNSSwitch(strings.NewReader(`
+# comment
+passwd:         files systemd
+services:       db [NOTFOUND=return unavail=Continue bogus foo=bar] files[!SUCCESS=continue]
+protocols:files # trailing comment
+ethers:
+malformed
+: files
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nss).To(HaveLen(4))
+			Expect(nss).To(HaveKeyWithValue("services", []NSSwitchSource{
+				{Name: "db", Criteria: Criteria{NotFound: ActionReturn, Unavail: ActionContinue}},
+				{Name: "files", Criteria: Criteria{NotFound: ActionContinue, Unavail: ActionContinue}},
+			}))
+			Expect(nss).To(HaveKeyWithValue("protocols", []NSSwitchSource{{Name: "files"}}))
+			Expect(nss).To(HaveKeyWithValue("ethers", []NSSwitchSource{}))
+		})
+
+		It("loads nsswitch configurations", func() {
+			_, err := LoadNSSwitch("test/non-existing-nsswitch.conf")
+			Expect(err).To(HaveOccurred())
+			nss, err := LoadNSSwitch("test/nsswitch.conf")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nss).To(HaveKey("services"))
+		})
+
+		It("builds chains", func() {
+			nss, err := ParseNSSwitch(strings.NewReader(`
+services: files [NOTFOUND=return] netdb
+protocols: db netdb
+`))
+			Expect(err).NotTo(HaveOccurred())
+
+			schain := nss.ServiceChain(map[string]ServiceSource{
+				"files": &files,
+				"netdb": DefaultServiceSource,
+			})
+			Expect(schain).To(HaveLen(2))
+			Expect(schain.ByName("crash", "tcp")).NotTo(BeNil())
+			Expect(schain.ByName("ssh", "tcp")).To(BeNil())
+
+			pchain := nss.ProtocolChain(map[string]ProtocolSource{"netdb": DefaultProtocolSource})
+			Expect(pchain).To(HaveLen(2))
+			Expect(pchain[0].Source).To(BeNil())
+			Expect(pchain.ByNumber(6)).NotTo(BeNil())
+		})
+
+	})
+
+})
@@ -0,0 +1,193 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// WatchFiles names the protocols, services, and EtherTypes files to watch. An
+// empty file name skips watching the corresponding file.
+type WatchFiles struct {
+	Protocols  string // protocols(5) file, such as "/etc/protocols".
+	Services   string // services(5) file, such as "/etc/services".
+	EtherTypes string // EtherTypes file, such as "/etc/ethertypes".
+}
+
+// EtcFiles names the well-known protocols, services, and EtherTypes files in
+// /etc.
+var EtcFiles = WatchFiles{
+	Protocols:  "/etc/protocols",
+	Services:   "/etc/services",
+	EtherTypes: "/etc/ethertypes",
+}
+
+// WatchEvent tells subscribers of a Watcher about a (re)load of a watched
+// file.
+type WatchEvent struct {
+	Filename string // name of the (re)loaded file.
+	// Err is non-nil if the file could not be loaded, in which case the
+	// last good index stays in place.
+	Err error
+}
+
+// Watcher loads protocols, services, and EtherTypes definitions from files,
+// reloading them whenever they change. The Watcher publishes new indices
+// atomically to the package-level Protocols, Services, and EtherTypes indices,
+// replacing their previous contents. On Linux, Watcher uses inotify, otherwise
+// it falls back to polling.
+type Watcher struct {
+	files WatchFiles
+	opts  ParseOptions
+
+	mu          sync.Mutex
+	protocols   *ProtocolIndex // last good protocols index, if watched.
+	subscribers map[uint64]func(WatchEvent)
+	nextID      uint64
+}
+
+// NewWatcher returns a new Watcher for the specified files, parsing them as
+// specified by the options. Please note that the file names in the options are
+// ignored, using the names of the watched files instead.
+func NewWatcher(files WatchFiles, opts ParseOptions) *Watcher {
+	return &Watcher{
+		files:       files,
+		opts:        opts,
+		subscribers: map[uint64]func(WatchEvent){},
+	}
+}
+
+// Subscribe registers the specified function to be called after each
+// (attempted) (re)load of a watched file, returning a function to unsubscribe
+// again. The function gets called from the Watcher's goroutine.
+func (w *Watcher) Subscribe(fn func(WatchEvent)) (unsubscribe func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id := w.nextID
+	w.nextID++
+	w.subscribers[id] = fn
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.subscribers, id)
+	}
+}
+
+// Watch loads the watched files and then keeps reloading them whenever they
+// change, until the passed context gets cancelled. Watch returns the context's
+// error when cancelled, or another error if watching the files fails.
+func (w *Watcher) Watch(ctx context.Context) error {
+	paths := []string{}
+	for _, path := range []string{w.files.Protocols, w.files.Services, w.files.EtherTypes} {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return errors.New("netdb: no files to watch")
+	}
+	return watchFiles(ctx, paths,
+		func() { _ = w.Reload() },
+		func(changed []string) { w.reload(changed...) })
+}
+
+// Reload (re)loads all watched files, regardless of whether they changed,
+// returning the joined errors of all failed loads.
+func (w *Watcher) Reload() error {
+	return w.reload(w.files.Protocols, w.files.Services, w.files.EtherTypes)
+}
+
+// reload (re)loads the specified files, publishing the updated indices and
+// notifying subscribers. As services refer to protocols, reloading protocols
+// always reloads services too.
+func (w *Watcher) reload(paths ...string) error {
+	var protocols, services, ethertypes bool
+	for _, path := range paths {
+		switch path {
+		case "":
+		case w.files.Protocols:
+			protocols, services = true, true
+		case w.files.Services:
+			services = true
+		case w.files.EtherTypes:
+			ethertypes = true
+		}
+	}
+	var errs []error
+	if protocols && w.files.Protocols != "" {
+		idx, err := LoadProtocolsWithOptions(w.files.Protocols, w.options(w.files.Protocols))
+		if err == nil {
+			w.mu.Lock()
+			w.protocols = &idx
+			w.mu.Unlock()
+			SetProtocols(idx)
+		}
+		errs = append(errs, w.notify(w.files.Protocols, err))
+	}
+	if services && w.files.Services != "" {
+		idx, err := LoadServicesWithOptions(w.files.Services, w.protocolIndex(), w.options(w.files.Services))
+		if err == nil {
+			SetServices(idx)
+		}
+		errs = append(errs, w.notify(w.files.Services, err))
+	}
+	if ethertypes && w.files.EtherTypes != "" {
+		idx, err := LoadEtherTypesWithOptions(w.files.EtherTypes, w.options(w.files.EtherTypes))
+		if err == nil {
+			SetEtherTypes(idx)
+		}
+		errs = append(errs, w.notify(w.files.EtherTypes, err))
+	}
+	return errors.Join(errs...)
+}
+
+// options returns the parse options for the named file.
+func (w *Watcher) options(name string) ParseOptions {
+	opts := w.opts
+	opts.Filename = name
+	return opts
+}
+
+// protocolIndex returns the protocols index to use when parsing services: the
+// last good watched protocols index, otherwise the package-level Protocols
+// index.
+func (w *Watcher) protocolIndex() ProtocolIndex {
+	w.mu.Lock()
+	protocols := w.protocols
+	w.mu.Unlock()
+	if protocols != nil {
+		return *protocols
+	}
+	idx := defaultProtocols.rlock()
+	defer defaultProtocols.runlock()
+	return *idx
+}
+
+// notify calls all subscribers with a WatchEvent for the named file and
+// (load) error, returning the error.
+func (w *Watcher) notify(name string, err error) error {
+	w.mu.Lock()
+	subscribers := make([]func(WatchEvent), 0, len(w.subscribers))
+	for _, fn := range w.subscribers {
+		subscribers = append(subscribers, fn)
+	}
+	w.mu.Unlock()
+	for _, fn := range subscribers {
+		fn(WatchEvent{Filename: name, Err: err})
+	}
+	return err
+}
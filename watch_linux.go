@@ -0,0 +1,115 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"slices"
+	"syscall"
+)
+
+// inotifyMask selects the inotify events signalling a changed file, both when
+// written in place as well as when atomically replaced by renaming.
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_TO |
+	syscall.IN_MOVED_FROM | syscall.IN_DELETE
+
+// watchFiles watches the specified files using inotify until the context gets
+// cancelled. It calls ready once the watches are in place and changed whenever
+// one or more of the files have changed.
+//
+// As configuration management tools often replace files instead of writing
+// them in place, watchFiles watches the directories containing the files.
+func watchFiles(ctx context.Context, paths []string, ready func(), changed func([]string)) error {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return os.NewSyscallError("inotify_init1", err)
+	}
+	// Thanks to IN_NONBLOCK, the file gets registered with the runtime poller,
+	// so that closing it unblocks any pending read.
+	f := os.NewFile(uintptr(fd), "inotify")
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stop:
+		}
+		f.Close()
+	}()
+
+	dirs := map[int32]string{}
+	watched := map[string]bool{}
+	for _, path := range paths {
+		dir := filepath.Dir(path)
+		if watched[dir] {
+			continue
+		}
+		watched[dir] = true
+		wd, err := syscall.InotifyAddWatch(fd, dir, inotifyMask)
+		if err != nil {
+			return os.NewSyscallError("inotify_add_watch", err)
+		}
+		dirs[int32(wd)] = dir
+	}
+	ready()
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := f.Read(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		changedPaths := changedFiles(buf[:n], dirs, paths)
+		if len(changedPaths) > 0 {
+			changed(changedPaths)
+		}
+	}
+}
+
+// changedFiles decodes the specified inotify events and returns the changed
+// files in the order they were specified. If the inotify event queue
+// overflowed, then events have been lost and all files are reported as
+// changed, so that they get fully reloaded.
+func changedFiles(events []byte, dirs map[int32]string, paths []string) []string {
+	changes := map[string]struct{}{}
+	for off := 0; off+syscall.SizeofInotifyEvent <= len(events); {
+		wd := int32(binary.NativeEndian.Uint32(events[off:]))
+		mask := binary.NativeEndian.Uint32(events[off+4:])
+		namelen := int(binary.NativeEndian.Uint32(events[off+12:]))
+		off += syscall.SizeofInotifyEvent
+		if mask&syscall.IN_Q_OVERFLOW != 0 {
+			return slices.Clone(paths)
+		}
+		name := string(bytes.TrimRight(events[off:min(off+namelen, len(events))], "\x00"))
+		off += namelen
+		if dir, ok := dirs[wd]; ok && name != "" {
+			changes[filepath.Join(dir, name)] = struct{}{}
+		}
+	}
+	changedPaths := []string{}
+	for _, path := range paths {
+		if _, ok := changes[filepath.Clean(path)]; ok {
+			changedPaths = append(changedPaths, path)
+		}
+	}
+	return changedPaths
+}
@@ -0,0 +1,63 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"encoding/binary"
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// inotifyEvent returns a raw inotify event for the specified watch descriptor,
+// mask, and name.
+func inotifyEvent(wd int32, mask uint32, name string) []byte {
+	namelen := 0
+	if name != "" {
+		namelen = (len(name) + 1 + 15) &^ 15 // NUL-terminated and padded.
+	}
+	event := make([]byte, syscall.SizeofInotifyEvent+namelen)
+	binary.NativeEndian.PutUint32(event[0:], uint32(wd))
+	binary.NativeEndian.PutUint32(event[4:], mask)
+	binary.NativeEndian.PutUint32(event[12:], uint32(namelen))
+	copy(event[syscall.SizeofInotifyEvent:], name)
+	return event
+}
+
+var _ = Describe("inotify events", func() {
+
+	dirs := map[int32]string{1: "/etc", 2: "/usr/share"}
+	paths := []string{"/etc/services", "/usr/share/protocols", "/etc/ethertypes"}
+
+	It("reports changed files in the order specified", func() {
+		var events []byte
+		events = append(events, inotifyEvent(1, syscall.IN_MOVED_TO, "ethertypes")...)
+		events = append(events, inotifyEvent(1, syscall.IN_CLOSE_WRITE, "hosts")...)
+		events = append(events, inotifyEvent(2, syscall.IN_DELETE, "protocols")...)
+		events = append(events, inotifyEvent(3, syscall.IN_DELETE, "services")...)
+		Expect(changedFiles(events, dirs, paths)).To(HaveExactElements(
+			"/usr/share/protocols", "/etc/ethertypes"))
+		Expect(changedFiles(nil, dirs, paths)).To(BeEmpty())
+	})
+
+	It("reports all files after an event queue overflow", func() {
+		var events []byte
+		events = append(events, inotifyEvent(1, syscall.IN_MOVED_TO, "ethertypes")...)
+		events = append(events, inotifyEvent(-1, syscall.IN_Q_OVERFLOW, "")...)
+		Expect(changedFiles(events, dirs, paths)).To(Equal(paths))
+	})
+
+})
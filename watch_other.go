@@ -0,0 +1,73 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+//go:build !linux
+
+package netdb
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// pollInterval is the interval between checks for changed files on platforms
+// without inotify support.
+const pollInterval = 2 * time.Second
+
+// fileState is the state of a file relevant to detecting changes.
+type fileState struct {
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{modTime: info.ModTime(), size: info.Size(), exists: true}
+}
+
+// watchFiles polls the specified files for changes until the context gets
+// cancelled. It calls ready once the initial file states have been taken and
+// changed whenever one or more of the files have changed.
+func watchFiles(ctx context.Context, paths []string, ready func(), changed func([]string)) error {
+	states := make([]fileState, len(paths))
+	for idx, path := range paths {
+		states[idx] = statFile(path)
+	}
+	ready()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		changedPaths := []string{}
+		for idx, path := range paths {
+			if state := statFile(path); state != states[idx] {
+				states[idx] = state
+				changedPaths = append(changedPaths, path)
+			}
+		}
+		if len(changedPaths) > 0 {
+			changed(changedPaths)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("watching files", func() {
+
+	var dir string
+
+	writeFile := func(name string, content string) {
+		GinkgoHelper()
+		// write atomically by renaming, as configuration management tools do.
+		tmp := filepath.Join(dir, "."+name+".tmp")
+		Expect(os.WriteFile(tmp, []byte(content), 0644)).To(Succeed())
+		Expect(os.Rename(tmp, filepath.Join(dir, name))).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		DeferCleanup(func() {
+			Services = ServiceIndex{}
+			Protocols = ProtocolIndex{}
+			EtherTypes = EtherTypeIndex{}
+		})
+	})
+
+	It("rejects watching nothing", func() {
+		Expect(NewWatcher(WatchFiles{}, ParseOptions{}).Watch(context.Background())).
+			To(MatchError(ContainSubstring("no files to watch")))
+	})
+
+	It("reloads changed files and keeps the last good index", func() {
+		writeFile("protocols", "foobar 12\n")
+		writeFile("services", "crash 666/foobar\n")
+		writeFile("ethertypes", "test 9000\n")
+
+		w := NewWatcher(WatchFiles{
+			Protocols:  filepath.Join(dir, "protocols"),
+			Services:   filepath.Join(dir, "services"),
+			EtherTypes: filepath.Join(dir, "ethertypes"),
+		}, ParseOptions{Mode: ParseStrict})
+		events := make(chan WatchEvent, 10)
+		unsubscribe := w.Subscribe(func(ev WatchEvent) { events <- ev })
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		done := make(chan error)
+		go func() { done <- w.Watch(ctx) }()
+
+		for _, name := range []string{"protocols", "services", "ethertypes"} {
+			Eventually(events).Should(Receive(Equal(WatchEvent{Filename: filepath.Join(dir, name)})))
+		}
+		Expect(ServiceByName("crash", "foobar")).NotTo(BeNil())
+		Expect(EtherTypeByName("test")).NotTo(BeNil())
+
+		writeFile("services", "bang 667/foobar\n")
+		Eventually(events).Should(Receive(Equal(WatchEvent{Filename: filepath.Join(dir, "services")})))
+		Expect(ServiceByName("crash", "foobar")).To(BeNil())
+		Expect(ServiceByName("bang", "foobar")).NotTo(BeNil())
+
+		// Changing protocols also reloads the services depending on them.
+		writeFile("protocols", "foobar 42\n")
+		Eventually(events).Should(Receive(Equal(WatchEvent{Filename: filepath.Join(dir, "protocols")})))
+		Eventually(events).Should(Receive(Equal(WatchEvent{Filename: filepath.Join(dir, "services")})))
+		Expect(ServiceByName("bang", "foobar")).To(PointTo(MatchFields(IgnoreExtras, Fields{
+			"Protocol": PointTo(MatchFields(IgnoreExtras, Fields{
+				"Number": Equal(uint8(42)),
+			})),
+		})))
+
+		Expect(os.WriteFile(filepath.Join(dir, "ethertypes"), []byte("test 90x0\n"), 0644)).To(Succeed())
+		Eventually(events).Should(Receive(
+			HaveField("Err", MatchError(ContainSubstring("invalid EtherType number")))))
+		Expect(EtherTypeByName("test")).NotTo(BeNil())
+
+		unsubscribe()
+		writeFile("ethertypes", "foo 9000\n")
+		Eventually(func() *EtherType { return EtherTypeByName("foo") }).ShouldNot(BeNil())
+		Consistently(events, 100*time.Millisecond).ShouldNot(Receive())
+
+		cancel()
+		Eventually(done).Should(Receive(MatchError(context.Canceled)))
+	})
+
+})
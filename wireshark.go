@@ -0,0 +1,164 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// LoadWiresharkServices returns a ServiceIndex object initialized from the
+// definitions in the named file in Wireshark "services" format.
+func LoadWiresharkServices(name string, protos ProtocolIndex) (ServiceIndex, error) {
+	return LoadWiresharkServicesWithOptions(name, protos, ParseOptions{})
+}
+
+// LoadWiresharkServicesWithOptions returns a ServiceIndex object initialized
+// from the definitions in the named file in Wireshark "services" format,
+// parsing it as specified by the options. If the options don't specify a file
+// name, then the specified name is used. In ParseCollectAll mode, the returned
+// index contains all well-formed definitions, even when a ParseErrors error is
+// returned.
+func LoadWiresharkServicesWithOptions(name string, protos ProtocolIndex, opts ParseOptions) (ServiceIndex, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return NewServiceIndex(nil), err
+	}
+	defer f.Close()
+	if opts.Filename == "" {
+		opts.Filename = name
+	}
+	services, err := ParseWiresharkServicesWithOptions(f, protos, opts)
+	if services == nil {
+		return NewServiceIndex(nil), err
+	}
+	return NewServiceIndex(services), err
+}
+
+// ParseWiresharkServices parses network service definitions in Wireshark
+// "services" format from the given Reader and returns them as a list of
+// Service(s). Malformed definitions as well as definitions with protocols not
+// in the specified ProtocolIndex are silently skipped.
+func ParseWiresharkServices(r io.Reader, p ProtocolIndex) ([]Service, error) {
+	return ParseWiresharkServicesWithOptions(r, p, ParseOptions{})
+}
+
+// ParseWiresharkServicesWithOptions parses network service definitions in
+// Wireshark "services" format from the given Reader as specified by the
+// options and returns them as a list of Service(s).
+//
+// In contrast to services(5), a single line can define a service for multiple
+// comma-separated ports and port ranges, as well as for multiple
+// slash-separated protocols at once, such as:
+//
+//	tcpmux	1/tcp/udp	# TCP Port Service Multiplexer
+//	compressnet	2-3/tcp/udp	# Management Utility
+//	http	80,8080/tcp	# World Wide Web HTTP
+//
+// Each line results in a separate Service for each combination of port (or
+// port range) and protocol, in the order of the protocols and then ports.
+// Port ranges result in port range Service(s).
+func ParseWiresharkServicesWithOptions(r io.Reader, p ProtocolIndex, opts ParseOptions) ([]Service, error) {
+	services := []Service{}
+	lp := lineParser{opts: opts}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lp.next()
+		line := strings.TrimSpace(scanner.Text())
+		definition, comment := splitComment(line)
+		fields := strings.Fields(definition)
+		if len(fields) == 0 {
+			continue // skip empty lines and comment lines.
+		}
+		if len(fields) < 2 {
+			if err := lp.malformed(line, "missing port/protocol", false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		ports, protocols, err := parsePortsProtocols(fields[1])
+		if err != nil {
+			if err := lp.malformed(line, err.Error(), false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var protos []*Protocol
+		for _, protocol := range protocols {
+			proto, ok := p.Names[protocol]
+			if !ok && !opts.KeepUnknownProtocols {
+				break
+			}
+			protos = append(protos, proto)
+		}
+		if len(protos) != len(protocols) {
+			if err := lp.malformed(line, "unknown protocol", false); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		for idx, protocol := range protocols {
+			for _, port := range ports {
+				services = append(services, Service{
+					Name:         fields[0],
+					Port:         port[0],
+					LastPort:     port[1],
+					ProtocolName: protocol,
+					Protocol:     protos[idx],
+					Aliases:      fields[2:],
+					Comment:      comment,
+				})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return services, lp.err()
+}
+
+// parsePortsProtocols parses a "ports/protocol[/protocol...]" field, where
+// ports is a comma-separated list of ports and port ranges, followed by one or
+// more slash-separated protocol names. Each returned port consists of the
+// first and last port number, where the last port number is zero for a single
+// port.
+func parsePortsProtocols(field string) (ports [][2]int, protocols []string, err error) {
+	portlist, protolist, ok := strings.Cut(field, "/")
+	if !ok {
+		return nil, nil, errors.New("invalid port/protocol")
+	}
+	for _, item := range strings.Split(portlist, ",") {
+		first, last, err := parsePortRange(item)
+		if err != nil {
+			return nil, nil, err
+		}
+		ports = append(ports, [2]int{first, last})
+	}
+	for _, protocol := range strings.Split(protolist, "/") {
+		if protocol == "" {
+			return nil, nil, errors.New("invalid port/protocol")
+		}
+		protocols = append(protocols, protocol)
+	}
+	return ports, protocols, nil
+}
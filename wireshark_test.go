@@ -0,0 +1,176 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+var _ = Describe("Wireshark services", func() {
+
+	var protos ProtocolIndex
+
+	BeforeEach(func() {
+		protos = NewProtocolIndex(BuiltinProtocols)
+	})
+
+	Context("parsing descriptions", func() {
+
+		It("returns correct descriptions", func() {
+			s, err := ParseWiresharkServices(strings.NewReader(`
+# A comment
+http	80,8080/tcp	# World Wide Web HTTP
+foo	1-5,7/tcp/udp
+`), protos)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(HaveExactElements(
+				MatchFields(IgnoreExtras, Fields{
+					"Name":         Equal("http"),
+					"Port":         Equal(80),
+					"LastPort":     BeZero(),
+					"ProtocolName": Equal("tcp"),
+					"Protocol":     PointTo(HaveField("Number", uint8(6))),
+					"Comment":      Equal("World Wide Web HTTP"),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Name":         Equal("http"),
+					"Port":         Equal(8080),
+					"ProtocolName": Equal("tcp"),
+					"Comment":      Equal("World Wide Web HTTP"),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Name":         Equal("foo"),
+					"Port":         Equal(1),
+					"LastPort":     Equal(5),
+					"ProtocolName": Equal("tcp"),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Name":         Equal("foo"),
+					"Port":         Equal(7),
+					"ProtocolName": Equal("tcp"),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Name":         Equal("foo"),
+					"Port":         Equal(1),
+					"LastPort":     Equal(5),
+					"ProtocolName": Equal("udp"),
+					"Protocol":     PointTo(HaveField("Number", uint8(17))),
+				}),
+				MatchFields(IgnoreExtras, Fields{
+					"Name":         Equal("foo"),
+					"Port":         Equal(7),
+					"ProtocolName": Equal("udp"),
+				}),
+			))
+		})
+
+		It("parses entries of Wireshark's services file", func() {
+			s, err := ParseWiresharkServices(strings.NewReader(`
+# The format is the same as that used for services(5). It is allowed to merge
+# identical protocols, for example:
+#   foo 64/tcp
+#   foo 64/udp
+# becomes
+#   foo 64/tcp/udp
+#
+
+tcpmux 1/tcp/udp
+compressnet 2-3/tcp/udp
+`), protos)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(HaveExactElements(
+				And(HaveField("Name", "tcpmux"), HaveField("Port", 1), HaveField("ProtocolName", "tcp")),
+				And(HaveField("Name", "tcpmux"), HaveField("Port", 1), HaveField("ProtocolName", "udp")),
+				And(HaveField("Name", "compressnet"), HaveField("Port", 2), HaveField("LastPort", 3),
+					HaveField("ProtocolName", "tcp")),
+				And(HaveField("Name", "compressnet"), HaveField("Port", 2), HaveField("LastPort", 3),
+					HaveField("ProtocolName", "udp")),
+			))
+		})
+
+		It("silently skips malformed definitions", func() {
+			s, err := ParseWiresharkServices(strings.NewReader(`
+foo
+foo 80
+foo 80/
+foo 80/tcp/
+foo 80//tcp
+foo 80,/tcp
+foo 5-1/tcp
+foo 80/tcp,udp
+foo 80/tcp/abc
+http 80/tcp
+`), protos)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(HaveExactElements(HaveField("Name", "http")))
+		})
+
+		It("collects all errors", func() {
+			s, err := ParseWiresharkServicesWithOptions(strings.NewReader("foo 80\nfoo 80/abc\nhttp 80/tcp\n"),
+				protos, ParseOptions{Mode: ParseCollectAll})
+			Expect(err).To(HaveLen(2))
+			Expect(err.Error()).To(And(
+				ContainSubstring("invalid port/protocol"),
+				ContainSubstring("unknown protocol")))
+			Expect(s).To(HaveExactElements(HaveField("Name", "http")))
+		})
+
+		It("keeps unknown protocols", func() {
+			s, err := ParseWiresharkServicesWithOptions(strings.NewReader("foo 80/tcp/abc\n"),
+				protos, ParseOptions{KeepUnknownProtocols: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s).To(HaveExactElements(
+				HaveField("Protocol", Not(BeNil())),
+				And(HaveField("ProtocolName", "abc"), HaveField("Protocol", BeNil())),
+			))
+		})
+
+	})
+
+	Context("loading", func() {
+
+		It("reports an error for a non-existing file", func() {
+			_, err := LoadWiresharkServices("test/non-existing-wireshark-services", protos)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("loads and indexes", func() {
+			idx, err := LoadWiresharkServices("test/wireshark-services", protos)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(slices.Collect(idx.All())).To(HaveLen(5))
+			Expect(idx.ByName("tcpmux", "udp").Port).To(Equal(1))
+			Expect(idx.ByPort(8080, "tcp").Name).To(Equal("http"))
+			Expect(idx.ByPort(3, "udp").Name).To(Equal("foo"))
+			Expect(idx.ByPort(3, "tcp")).To(BeNil())
+		})
+
+		It("merges Wireshark overrides", func() {
+			idx := NewServiceIndex([]Service{
+				{Name: "http-alt", Port: 8080, ProtocolName: "tcp"},
+			})
+			overrides, err := LoadWiresharkServices("test/wireshark-services", protos)
+			Expect(err).NotTo(HaveOccurred())
+			idx.MergeIndex(overrides)
+			Expect(idx.ByPort(8080, "tcp").Name).To(Equal("http"))
+		})
+
+	})
+
+})
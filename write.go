@@ -0,0 +1,175 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"strconv"
+	"strings"
+)
+
+// WriteServices writes the specified services in services(5) format to the
+// given Writer, with the names, ports and protocols, aliases, and comments
+// aligned in columns.
+func WriteServices(w io.Writer, services []Service) error {
+	_, err := writeServices(w, pointersOf(services))
+	return err
+}
+
+// WriteTo writes all services of this index in services(5) format to the
+// given Writer, in the order of their original definitions. It implements
+// io.WriterTo.
+func (i *ServiceIndex) WriteTo(w io.Writer) (int64, error) {
+	return writeServices(w, i.All())
+}
+
+func writeServices(w io.Writer, services iter.Seq[*Service]) (int64, error) {
+	rows := [][]string{}
+	for service := range services {
+		rows = append(rows, []string{
+			service.Name,
+			portRangeOf(service) + "/" + service.ProtocolName,
+			strings.Join(service.Aliases, " "),
+			commentOf(service.Comment),
+		})
+	}
+	return writeColumns(w, rows)
+}
+
+// WriteProtocols writes the specified protocols in protocols(5) format to the
+// given Writer, with the names, numbers, aliases, and comments aligned in
+// columns.
+func WriteProtocols(w io.Writer, protos []Protocol) error {
+	_, err := writeProtocols(w, pointersOf(protos))
+	return err
+}
+
+// WriteTo writes all protocols of this index in protocols(5) format to the
+// given Writer, in the order of their original definitions. It implements
+// io.WriterTo.
+func (i *ProtocolIndex) WriteTo(w io.Writer) (int64, error) {
+	return writeProtocols(w, i.All())
+}
+
+func writeProtocols(w io.Writer, protos iter.Seq[*Protocol]) (int64, error) {
+	rows := [][]string{}
+	for proto := range protos {
+		rows = append(rows, []string{
+			proto.Name,
+			strconv.FormatUint(uint64(proto.Number), 10),
+			strings.Join(proto.Aliases, " "),
+			commentOf(proto.Comment),
+		})
+	}
+	return writeColumns(w, rows)
+}
+
+// WriteEtherTypes writes the specified EtherTypes in the /etc/ethertypes
+// format to the given Writer, with the names, (hexadecimal) numbers, aliases,
+// and comments aligned in columns.
+func WriteEtherTypes(w io.Writer, ethertypes []EtherType) error {
+	_, err := writeEtherTypes(w, pointersOf(ethertypes))
+	return err
+}
+
+// WriteTo writes all EtherTypes of this index in the /etc/ethertypes format to
+// the given Writer, in the order of their original definitions. It implements
+// io.WriterTo.
+func (i *EtherTypeIndex) WriteTo(w io.Writer) (int64, error) {
+	return writeEtherTypes(w, i.All())
+}
+
+func writeEtherTypes(w io.Writer, ethertypes iter.Seq[*EtherType]) (int64, error) {
+	rows := [][]string{}
+	for ethertype := range ethertypes {
+		rows = append(rows, []string{
+			ethertype.Name,
+			fmt.Sprintf("%04X", ethertype.Number),
+			strings.Join(ethertype.Aliases, " "),
+			commentOf(ethertype.Comment),
+		})
+	}
+	return writeColumns(w, rows)
+}
+
+// portRangeOf returns the port number of the specified service, or its port
+// range in the form of "first-last".
+func portRangeOf(service *Service) string {
+	first, last := service.PortRange()
+	if first == last {
+		return strconv.Itoa(first)
+	}
+	return strconv.Itoa(first) + "-" + strconv.Itoa(last)
+}
+
+// commentOf returns the specified comment text as a "#" comment, or an empty
+// string if there is no comment text.
+func commentOf(comment string) string {
+	if comment == "" {
+		return ""
+	}
+	return "# " + comment
+}
+
+// writeColumns writes the rows of cells to the given Writer, aligning the
+// cells in columns separated by at least two spaces. Trailing empty cells are
+// dropped, so that lines never end in whitespace.
+func writeColumns(w io.Writer, rows [][]string) (int64, error) {
+	widths := []int{}
+	for _, row := range rows {
+		for col, cell := range row {
+			if col >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[col] = max(widths[col], len(cell))
+		}
+	}
+	var total int64
+	var line strings.Builder
+	for _, row := range rows {
+		line.Reset()
+		last := len(row) - 1
+		for last > 0 && row[last] == "" {
+			last--
+		}
+		for col, cell := range row[:last+1] {
+			line.WriteString(cell)
+			if col < last {
+				line.WriteString(strings.Repeat(" ", widths[col]-len(cell)+2))
+			}
+		}
+		line.WriteByte('\n')
+		n, err := io.WriteString(w, line.String())
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// pointersOf returns an iterator over pointers to the elements of the
+// specified slice.
+func pointersOf[E any](entries []E) iter.Seq[*E] {
+	return func(yield func(*E) bool) {
+		for idx := range entries {
+			if !yield(&entries[idx]) {
+				return
+			}
+		}
+	}
+}
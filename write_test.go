@@ -0,0 +1,111 @@
+// Copyright 2024 Harald Albrecht.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may not
+// use this file except in compliance with the License. You may obtain a copy
+// of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package netdb
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// failingWriter fails all writes.
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("write failed") }
+
+var _ = Describe("writing", func() {
+
+	It("writes services in aligned columns", func() {
+		protos := NewProtocolIndex(BuiltinProtocols)
+		services, err := ParseServices(strings.NewReader(`
+tcpmux 1/tcp
+discard 9/udp sink null
+netbios-ns 137/udp		# NETBIOS Name Service
+x11 6000-6063/tcp
+`), protos)
+		Expect(err).NotTo(HaveOccurred())
+		var out strings.Builder
+		Expect(WriteServices(&out, services)).To(Succeed())
+		Expect(out.String()).To(Equal(`tcpmux      1/tcp
+discard     9/udp          sink null
+netbios-ns  137/udp                   # NETBIOS Name Service
+x11         6000-6063/tcp
+`))
+	})
+
+	It("writes protocols in aligned columns", func() {
+		protos, err := ParseProtocols(strings.NewReader(`
+ip 0 IP
+ipv6-icmp 58 IPv6-ICMP	# ICMP for IPv6
+foo 123
+`))
+		Expect(err).NotTo(HaveOccurred())
+		var out strings.Builder
+		Expect(WriteProtocols(&out, protos)).To(Succeed())
+		Expect(out.String()).To(Equal(`ip         0    IP
+ipv6-icmp  58   IPv6-ICMP  # ICMP for IPv6
+foo        123
+`))
+	})
+
+	It("writes EtherTypes in aligned columns", func() {
+		ethertypes, err := ParseEtherTypes(strings.NewReader(`
+IPv4	0800	ip ip4		# Internet IP (IPv4)
+X25	0805
+RoMON	88BF		# MikroTik RoMON (unofficial)
+`))
+		Expect(err).NotTo(HaveOccurred())
+		var out strings.Builder
+		Expect(WriteEtherTypes(&out, ethertypes)).To(Succeed())
+		Expect(out.String()).To(Equal(`IPv4   0800  ip ip4  # Internet IP (IPv4)
+X25    0805
+RoMON  88BF          # MikroTik RoMON (unofficial)
+`))
+	})
+
+	It("round-trips the builtin databases", func() {
+		protos := NewProtocolIndex(BuiltinProtocols)
+		var out strings.Builder
+		n, err := protos.WriteTo(&out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(int64(out.Len())))
+		p, err := ParseProtocolsWithOptions(strings.NewReader(out.String()), ParseOptions{Mode: ParseStrict})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p).To(Equal(BuiltinProtocols))
+
+		services := NewServiceIndex(BuiltinServices)
+		out.Reset()
+		_, err = services.WriteTo(&out)
+		Expect(err).NotTo(HaveOccurred())
+		s, err := ParseServicesWithOptions(strings.NewReader(out.String()), protos, ParseOptions{Mode: ParseStrict})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s).To(Equal(BuiltinServices))
+
+		ethertypes := NewEtherTypeIndex(BuiltinEtherTypes)
+		out.Reset()
+		_, err = ethertypes.WriteTo(&out)
+		Expect(err).NotTo(HaveOccurred())
+		e, err := ParseEtherTypesWithOptions(strings.NewReader(out.String()), ParseOptions{Mode: ParseStrict})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(e).To(Equal(BuiltinEtherTypes))
+	})
+
+	It("reports write errors", func() {
+		Expect(WriteProtocols(failingWriter{}, BuiltinProtocols)).NotTo(Succeed())
+	})
+
+})